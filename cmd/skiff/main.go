@@ -3,11 +3,16 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/skiff-sh/skiff/cmd/cmdinit"
 	"github.com/skiff-sh/skiff/pkg/interact"
 )
 
+// The conventional exit code for a process interrupted by SIGINT.
+const exitCodeCancelled = 130
+
 func main() {
 	cmd, err := cmdinit.NewCommand()
 	if err != nil {
@@ -15,10 +20,16 @@ func main() {
 		os.Exit(1)
 	}
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Restores the default behavior once cancelled so a second signal force quits e.g. during a rollback.
+	context.AfterFunc(ctx, stop)
 
 	err = cmd.Run(ctx, os.Args)
-	if err != nil {
+	if interact.IsCancelled(err) {
+		interact.Warn("Cancelled.")
+		os.Exit(exitCodeCancelled)
+	} else if err != nil {
 		interact.Error(err.Error())
 		os.Exit(1)
 	}
@@ -16,6 +16,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/skiff-sh/config/ptr"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/skiff-sh/skiff/pkg/execcmd"
@@ -25,9 +26,12 @@ import (
 
 	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
 
+	"github.com/skiff-sh/skiff/pkg/answers"
 	"github.com/skiff-sh/skiff/pkg/collection"
+	"github.com/skiff-sh/skiff/pkg/fields"
 	"github.com/skiff-sh/skiff/pkg/interact"
 	"github.com/skiff-sh/skiff/pkg/protoencode"
+	"github.com/skiff-sh/skiff/pkg/schema"
 	"github.com/skiff-sh/skiff/pkg/testutil"
 )
 
@@ -36,6 +40,13 @@ type CliTestSuite struct {
 }
 
 func (c *CliTestSuite) TestHelp() {
+	pkgPath := c.writePackage(c.T().TempDir(), &v1alpha1.Package{
+		Name: "help-pkg",
+		Schema: &v1alpha1.Schema{Fields: []*v1alpha1.Field{
+			{Name: "name", Type: ptr.Ptr(v1alpha1.Field_string)},
+		}},
+	})
+
 	type output struct {
 		Stdout *bytes.Buffer
 	}
@@ -61,6 +72,13 @@ func (c *CliTestSuite) TestHelp() {
 				c.NotEmpty(o.Stdout.String())
 			},
 		},
+		"add help with a package": {
+			// The values file is the same path as the package to check only the package is removed.
+			Args: []string{"add", "-f", pkgPath, "--help", pkgPath},
+			ExpectedFunc: func(o *output) {
+				c.Contains(o.Stdout.String(), "--help-pkg.name")
+			},
+		},
 		"build help": {
 			Args: []string{"build", "--help"},
 			ExpectedFunc: func(o *output) {
@@ -99,6 +117,48 @@ func (c *CliTestSuite) TestHelp() {
 	}
 }
 
+func (c *CliTestSuite) TestCache() {
+	exaDir, err := CloneExample(os.DirFS(ExamplesPath()), "go-fiber-controller")
+	if !c.NoError(err) {
+		return
+	}
+	defer func() {
+		_ = os.RemoveAll(exaDir)
+	}()
+
+	oldBuildDir := settings.BuildDirFunc
+	buildDir := c.T().TempDir()
+	settings.BuildDirFunc = func() (string, error) {
+		return buildDir, nil
+	}
+	defer func() {
+		settings.BuildDirFunc = oldBuildDir
+	}()
+
+	defer c.SetWd(exaDir)()
+
+	// Building compiles the plugins.
+	_, ok := c.buildExample(exaDir)
+	if !ok {
+		return
+	}
+
+	run := func(args ...string) string {
+		cli, err := New()
+		c.Require().NoError(err)
+
+		buf := bytes.NewBuffer(nil)
+		cli.Command.CLI.Writer = buf
+		c.Require().NoError(cli.Command.Run(c.T().Context(), append([]string{"skiff", "cache"}, args...)))
+		return buf.String()
+	}
+
+	c.NotContains(run("list"), "0 compiled plugins")
+
+	run("clear")
+	c.Contains(run("list"), "0 compiled plugins")
+}
+
 func (c *CliTestSuite) TestBuild() {
 	type params struct {
 		// The directory housing the cloned example folder.
@@ -236,6 +296,31 @@ func (c *CliTestSuite) TestAdd() {
 		ExpectedErr string
 	}
 
+	// Writes two packages sharing project.module within dir. The second declares it as moduleType.
+	sharedPackages := func(dir string, moduleType v1alpha1.Field_Type) []string {
+		out := make([]string, 0, 2)
+		for _, name := range []string{"pkg-a", "pkg-b"} {
+			typ := v1alpha1.Field_string
+			if name == "pkg-b" {
+				typ = moduleType
+			}
+			out = append(out, c.writePackage(dir, &v1alpha1.Package{
+				Name: name,
+				Schema: &v1alpha1.Schema{Fields: []*v1alpha1.Field{
+					{Name: "project.module", Type: ptr.Ptr(typ)},
+				}},
+				Files: []*v1alpha1.File{
+					{
+						Path:   "module.tmpl",
+						Target: name + ".txt",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr("{{ .project.module }}")},
+					},
+				},
+			}))
+		}
+		return out
+	}
+
 	tests := map[string]test{
 		"input all data interactive": {
 			Args: func(b *BuildCmdOutput) []string {
@@ -276,7 +361,414 @@ func (c *CliTestSuite) TestAdd() {
 				}
 			},
 			Expected: func(p *output) {
-				c.ErrorContains(p.Err, "Required flags")
+				c.ErrorContains(
+					p.Err,
+					"missing values for --create-http-route.method, --create-http-route.name, --create-http-route.path",
+				)
+			},
+		},
+		"values files non interactive": {
+			Args: func(b *BuildCmdOutput) []string {
+				base := filepath.Join(b.RootDir, "base.yaml")
+				override := filepath.Join(b.RootDir, "override.json")
+				baseVals := "create-http-route:\n  name: derp\n  method: GET\n  path: /base\n"
+				_ = os.WriteFile(base, []byte(baseVals), 0o600)
+				_ = os.WriteFile(override, []byte(`{"create-http-route": {"path": "/override"}}`), 0o600)
+				return []string{
+					"--root",
+					b.RootDir,
+					"-p",
+					"all",
+					"-y",
+					"--non-i",
+					"-f",
+					base,
+					"-f",
+					override,
+					"--create-http-route.method=POST",
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContainsAll(p.BuildRoot, filepath.Join("controller", "derp.go"), []string{"POST", "/override"})
+			},
+		},
+		"values file set with = and reset answers": {
+			Args: func(b *BuildCmdOutput) []string {
+				fp := filepath.Join(b.RootDir, "values.yaml")
+				_ = os.WriteFile(fp, []byte("create-http-route:\n  name: derp\n  method: GET\n  path: /eq\n"), 0o600)
+				return []string{
+					"--root",
+					b.RootDir,
+					"-p",
+					"all",
+					"-y",
+					"--non-i",
+					"--reset-answers",
+					"--values=" + fp,
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContainsAll(p.BuildRoot, filepath.Join("controller", "derp.go"), []string{"GET", "/eq"})
+			},
+		},
+		"env vars non interactive": {
+			Args: func(b *BuildCmdOutput) []string {
+				c.T().Setenv("SKIFF_CREATE_HTTP_ROUTE_NAME", "derp")
+				c.T().Setenv("SKIFF_CREATE_HTTP_ROUTE_METHOD", "PATCH")
+				c.T().Setenv("SKIFF_CREATE_HTTP_ROUTE_PATH", "/env")
+				return []string{
+					"--root",
+					b.RootDir,
+					"-p",
+					"all",
+					"-y",
+					"--non-i",
+					"--create-http-route.path=/flag",
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContainsAll(p.BuildRoot, filepath.Join("controller", "derp.go"), []string{"PATCH", "/flag"})
+			},
+		},
+		"previous answers non interactive": {
+			Args: func(b *BuildCmdOutput) []string {
+				c.saveAnswers(b.RootDir, "create-http-route:\n  name: derp\n  method: PUT\n  path: /answer\n")
+				return []string{
+					"--root",
+					b.RootDir,
+					"-p",
+					"all",
+					"-y",
+					"--non-i",
+					"--create-http-route.path=/flag",
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContainsAll(p.BuildRoot, filepath.Join("controller", "derp.go"), []string{"PUT", "/flag"})
+			},
+		},
+		"only typed answers are remembered": {
+			Args: func(b *BuildCmdOutput) []string {
+				c.T().Setenv("SKIFF_CREATE_HTTP_ROUTE_NAME", "secretenv")
+				return []string{
+					"--root",
+					b.RootDir,
+					"-y",
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Inputs: []testutil.TeaInputs{
+				testutil.Inputs(tea.KeyLeft, tea.KeyEnter), // Grant access.
+				testutil.Inputs(
+					tea.KeyDown, tea.KeyEnter, // provide the method
+					"/typed", tea.KeyEnter, // provide the path
+				),
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				fp, err := answers.Path(p.Build.RootDir)
+				c.Require().NoError(err)
+				b, err := os.ReadFile(fp)
+				if c.NoError(err) {
+					c.Contains(string(b), "/typed")
+					c.NotContains(string(b), "secretenv")
+				}
+				info, err := os.Stat(fp)
+				if c.NoError(err) {
+					c.Equal(os.FileMode(0o600), info.Mode().Perm())
+				}
+			},
+		},
+		"previous answers are pre-filled": {
+			Args: func(b *BuildCmdOutput) []string {
+				c.saveAnswers(b.RootDir, "create-http-route:\n  name: derp\n  method: PUT\n  path: /answer\n")
+				return []string{"--root", b.RootDir, "-y", filepath.Join(b.OutputDir, "create-http-route.json")}
+			},
+			Inputs: []testutil.TeaInputs{
+				testutil.Inputs(tea.KeyLeft, tea.KeyEnter), // Grant access.
+				testutil.Inputs(
+					tea.KeyEnter, // accept the name
+					tea.KeyEnter, // accept the method
+					tea.KeyEnter, // accept the path
+				),
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContainsAll(p.BuildRoot, filepath.Join("controller", "derp.go"), []string{"PUT", "/answer"})
+			},
+		},
+		"reset answers": {
+			Args: func(b *BuildCmdOutput) []string {
+				c.saveAnswers(b.RootDir, "create-http-route:\n  name: derp\n  method: PUT\n  path: /answer\n")
+				return []string{
+					"--root",
+					b.RootDir,
+					"-p",
+					"all",
+					"--non-i",
+					"--reset-answers",
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(p.Err, "missing values for")
+			},
+		},
+		"env vars are validated": {
+			Args: func(b *BuildCmdOutput) []string {
+				c.T().Setenv("SKIFF_CREATE_HTTP_ROUTE_METHOD", "GETS")
+				return []string{
+					"--root",
+					b.RootDir,
+					"-p",
+					"all",
+					"--non-i",
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(p.Err, "method cannot be 'GETS'")
+			},
+		},
+		"values files are validated": {
+			Args: func(b *BuildCmdOutput) []string {
+				fp := filepath.Join(b.RootDir, "values.yaml")
+				_ = os.WriteFile(fp, []byte("create-http-route:\n  nam: derp\n  method: GETS\n"), 0o600)
+				return []string{
+					"--root",
+					b.RootDir,
+					"-p",
+					"all",
+					"--non-i",
+					"-f",
+					fp,
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(p.Err, "create-http-route.method: method cannot be 'GETS'")
+				c.ErrorContains(p.Err, "create-http-route.nam: unknown field")
+			},
+		},
+		"non interactive values files must provide every field": {
+			Args: func(b *BuildCmdOutput) []string {
+				fp := filepath.Join(b.RootDir, "values.yaml")
+				_ = os.WriteFile(fp, []byte("create-http-route:\n  name: derp\n"), 0o600)
+				return []string{
+					"--root",
+					b.RootDir,
+					"-p",
+					"all",
+					"--non-i",
+					"-f",
+					fp,
+					filepath.Join(b.OutputDir, "create-http-route.json"),
+				}
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(p.Err, "missing values for --create-http-route.method, --create-http-route.path")
+			},
+		},
+		"shared fields are asked once": {
+			Args: func(b *BuildCmdOutput) []string {
+				return append([]string{"--root", b.RootDir, "-y"}, sharedPackages(b.RootDir, v1alpha1.Field_string)...)
+			},
+			Inputs: []testutil.TeaInputs{
+				testutil.Inputs("github.com/skiff-sh/proj", tea.KeyEnter),
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContains(p.BuildRoot, "pkg-a.txt", "github.com/skiff-sh/proj")
+				c.FileContains(p.BuildRoot, "pkg-b.txt", "github.com/skiff-sh/proj")
+			},
+		},
+		"shared fields env var non interactive": {
+			Args: func(b *BuildCmdOutput) []string {
+				c.T().Setenv("SKIFF_PROJECT_MODULE", "github.com/skiff-sh/env")
+				return append(
+					[]string{"--root", b.RootDir, "-y", "--non-i"},
+					sharedPackages(b.RootDir, v1alpha1.Field_string)...,
+				)
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContains(p.BuildRoot, "pkg-a.txt", "github.com/skiff-sh/env")
+				c.FileContains(p.BuildRoot, "pkg-b.txt", "github.com/skiff-sh/env")
+			},
+		},
+		"shared fields flag non interactive": {
+			Args: func(b *BuildCmdOutput) []string {
+				c.T().Setenv("SKIFF_PROJECT_MODULE", "github.com/skiff-sh/env")
+				return append(
+					[]string{"--root", b.RootDir, "-y", "--non-i", "--project.module=github.com/skiff-sh/flag"},
+					sharedPackages(b.RootDir, v1alpha1.Field_string)...,
+				)
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContains(p.BuildRoot, "pkg-a.txt", "github.com/skiff-sh/flag")
+				c.FileContains(p.BuildRoot, "pkg-b.txt", "github.com/skiff-sh/flag")
+			},
+		},
+		"shared fields values file under another package": {
+			Args: func(b *BuildCmdOutput) []string {
+				fp := filepath.Join(b.RootDir, "values.yaml")
+				_ = os.WriteFile(fp, []byte("pkg-a:\n  project.module: github.com/skiff-sh/vals\n"), 0o600)
+				return append(
+					[]string{"--root", b.RootDir, "-y", "--non-i", "-f", fp},
+					sharedPackages(b.RootDir, v1alpha1.Field_string)...,
+				)
+			},
+			Expected: func(p *output) {
+				if !c.NoError(p.Err) {
+					return
+				}
+
+				c.FileContains(p.BuildRoot, "pkg-a.txt", "github.com/skiff-sh/vals")
+				c.FileContains(p.BuildRoot, "pkg-b.txt", "github.com/skiff-sh/vals")
+			},
+		},
+		"shared fields values files can't conflict": {
+			Args: func(b *BuildCmdOutput) []string {
+				fp := filepath.Join(b.RootDir, "values.yaml")
+				_ = os.WriteFile(fp, []byte("pkg-a:\n  project.module: A\npkg-b:\n  project.module: B\n"), 0o600)
+				return append(
+					[]string{"--root", b.RootDir, "-y", "--non-i", "--reset-answers", "-f", fp},
+					sharedPackages(b.RootDir, v1alpha1.Field_string)...,
+				)
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(p.Err, "pkg-b.project.module: shared field has a different value under pkg-a")
+				c.NoFileExists(filepath.Join(p.Build.RootDir, "pkg-a.txt"))
+			},
+		},
+		"shared fields must have the same type": {
+			Args: func(b *BuildCmdOutput) []string {
+				return append([]string{"--root", b.RootDir, "-y"}, sharedPackages(b.RootDir, v1alpha1.Field_number)...)
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(
+					p.Err,
+					"package pkg-b: shared field project.module has a different type or enum than in package pkg-a",
+				)
+			},
+		},
+		"shared fields must have the same enum": {
+			Args: func(b *BuildCmdOutput) []string {
+				args := []string{"--root", b.RootDir, "-y"}
+				for _, name := range []string{"pkg-a", "pkg-b"} {
+					args = append(args, c.writePackage(b.RootDir, &v1alpha1.Package{
+						Name: name,
+						Schema: &v1alpha1.Schema{Fields: []*v1alpha1.Field{
+							{
+								Name: "project.env",
+								Type: ptr.Ptr(v1alpha1.Field_string),
+								Enum: fields.NewListValue("dev", name),
+							},
+						}},
+					}))
+				}
+				return args
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(
+					p.Err,
+					"package pkg-b: shared field project.env has a different type or enum than in package pkg-a",
+				)
+			},
+		},
+		"written files are rolled back if a later package fails": {
+			Args: func(b *BuildCmdOutput) []string {
+				args := []string{"--root", b.RootDir, "-y"}
+				// The template of pkg-b fails after pkg-a is written.
+				for i, text := range []string{"a", "{{ index .nope 1 }}"} {
+					name := fmt.Sprintf("pkg-%c", 'a'+i)
+					args = append(args, c.writePackage(b.RootDir, &v1alpha1.Package{
+						Name: name,
+						Files: []*v1alpha1.File{
+							{
+								Path:   "file.tmpl",
+								Target: name + ".txt",
+								Source: &v1alpha1.File_Source{Text: ptr.Ptr(text)},
+							},
+						},
+					}))
+				}
+				return args
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(p.Err, "package pkg-b")
+				c.NoFileExists(filepath.Join(p.Build.RootDir, "pkg-a.txt"))
+				c.NoFileExists(filepath.Join(p.Build.RootDir, "pkg-b.txt"))
+			},
+		},
+		"shared fields can't collide with package fields": {
+			Args: func(b *BuildCmdOutput) []string {
+				args := append([]string{"--root", b.RootDir, "-y"}, sharedPackages(b.RootDir, v1alpha1.Field_string)...)
+				return append(
+					args,
+					c.writePackage(b.RootDir, &v1alpha1.Package{
+						Name: "pkg-c",
+						Schema: &v1alpha1.Schema{Fields: []*v1alpha1.Field{
+							{Name: "project", Type: ptr.Ptr(v1alpha1.Field_string)},
+						}},
+					}),
+				)
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(p.Err, "package pkg-c: field project collides with shared field project.module")
+			},
+		},
+		"shared field flags can't collide with package flags": {
+			Args: func(b *BuildCmdOutput) []string {
+				args := append([]string{"--root", b.RootDir, "-y"}, sharedPackages(b.RootDir, v1alpha1.Field_string)...)
+				return append(
+					args,
+					c.writePackage(b.RootDir, &v1alpha1.Package{
+						Name: "project",
+						Schema: &v1alpha1.Schema{Fields: []*v1alpha1.Field{
+							{Name: "module", Type: ptr.Ptr(v1alpha1.Field_string)},
+						}},
+					}),
+				)
+			},
+			Expected: func(p *output) {
+				c.ErrorContains(p.Err, "package project: flag --project.module collides with shared field project.module")
 			},
 		},
 		"no op if access is denied": {
@@ -316,6 +808,16 @@ func (c *CliTestSuite) TestAdd() {
 				return
 			}
 
+			// Isolate the answers of each test.
+			oldBuildDir := settings.BuildDirFunc
+			buildDir := c.T().TempDir()
+			settings.BuildDirFunc = func() (string, error) {
+				return buildDir, nil
+			}
+			defer func() {
+				settings.BuildDirFunc = oldBuildDir
+			}()
+
 			cmd, err := New()
 			if !c.NoError(err) {
 				return
@@ -353,7 +855,7 @@ func (c *CliTestSuite) TestAdd() {
 				BuildRoot:       filesystem.New(build.RootDir),
 				OriginalExample: filesystem.New(filepath.Join(ExamplesPath(), "go-fiber-controller")),
 			}
-			if err == nil {
+			if err == nil && mod != nil {
 				out.FinalOutput = testutil.Dump(mod.Output())
 			}
 			v.Expected(out)
@@ -364,6 +866,25 @@ func (c *CliTestSuite) TestAdd() {
 	}
 }
 
+func (c *CliTestSuite) saveAnswers(root, content string) {
+	vals, err := schema.ParseValues([]byte(content))
+	c.Require().NoError(err)
+
+	fp, err := answers.Path(root)
+	c.Require().NoError(err)
+	c.Require().NoError(answers.Save(fp, vals))
+}
+
+// writePackage writes pkg within dir and returns its path.
+func (c *CliTestSuite) writePackage(dir string, pkg *v1alpha1.Package) string {
+	b, err := protoencode.Marshal(pkg)
+	c.Require().NoError(err)
+
+	fp := filepath.Join(dir, pkg.GetName()+".json")
+	c.Require().NoError(os.WriteFile(fp, b, 0o600))
+	return fp
+}
+
 type BuildCmdOutput struct {
 	OutputDir string
 	RootDir   string
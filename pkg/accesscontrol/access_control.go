@@ -60,7 +60,8 @@ func (p *PluginAccessPolicy) Authorize(perm v1alpha1.PackagePermissions_Plugin)
 }
 
 type Granter interface {
-	RequestAccess(ctx context.Context, packageName string, requests []v1alpha1.PackagePermissions_Plugin) bool
+	// RequestAccess returns whether access was granted. Returns an error if the request was cancelled.
+	RequestAccess(ctx context.Context, packageName string, requests []v1alpha1.PackagePermissions_Plugin) (bool, error)
 }
 
 var _ Granter = (*TerminalGranter)(nil)
@@ -76,12 +77,11 @@ func (t *TerminalGranter) RequestAccess(
 	ctx context.Context,
 	packageName string,
 	requests []v1alpha1.PackagePermissions_Plugin,
-) bool {
+) (bool, error) {
 	permsStr := strings.Join(PermUsageListPretty(requests), "\n")
-	grant := interact.Confirm(ctx, func(c *huh.Confirm) *huh.Confirm {
+	return interact.Confirm(ctx, func(c *huh.Confirm) *huh.Confirm {
 		return c.Title(fmt.Sprintf("Package %s needs access to:\n\n%s\n", packageName, permsStr)).
 			Affirmative("Grant").
 			Negative("Deny")
 	})
-	return grant
 }
@@ -0,0 +1,160 @@
+package answers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/skiff-sh/skiff/pkg/fileutil"
+	"github.com/skiff-sh/skiff/pkg/schema"
+	"github.com/skiff-sh/skiff/pkg/settings"
+)
+
+const (
+	DirName = "answers"
+
+	// Number of bytes of the project root's hash used for the filename.
+	hashLen = 8
+)
+
+// Path returns the file storing the answers for the project root.
+func Path(projectRoot string) (string, error) {
+	dir, err := settings.BuildDir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(filepath.Clean(projectRoot)))
+	return filepath.Join(dir, DirName, hex.EncodeToString(sum[:hashLen])+".yaml"), nil
+}
+
+// file the answers of a project.
+type file struct {
+	// Answers of shared fields (see schema.IsShared) keyed by field name. Stored once since they're the same for every
+	// package.
+	Shared map[string]any `yaml:"shared,omitempty"`
+	// Answers of all other fields keyed by package name and then field name.
+	Packages schema.Values `yaml:"packages,omitempty"`
+}
+
+// Load loads the answers within fp for the fields of each package. Shared answers are loaded for every package that
+// declares the field. Answers for unknown packages or fields, or that are no longer valid, are dropped. Returns empty
+// values if fp does not exist.
+func Load(fp string, pkgFields map[string][]*schema.Field) (schema.Values, error) {
+	f, err := read(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	out := schema.Values{}
+	for pkg, fields := range pkgFields {
+		for _, field := range fields {
+			name := field.Proto.GetName()
+			var raw any
+			var ok bool
+			if schema.IsShared(name) {
+				raw, ok = f.Shared[name]
+			} else {
+				raw, ok = f.Packages[pkg][name]
+			}
+			if !ok {
+				continue
+			}
+
+			val, err := field.Coerce(raw)
+			if err != nil {
+				continue
+			}
+
+			if out[pkg] == nil {
+				out[pkg] = map[string]any{}
+			}
+			out[pkg][name] = val
+		}
+	}
+
+	return out, nil
+}
+
+// Save merges vals into the answers within fp. Shared answers are stored once regardless of the package they're given
+// under. Answers for packages not in vals are kept. The answers are only readable by the current user since they may
+// contain secrets.
+func Save(fp string, vals schema.Values) error {
+	f, err := read(fp)
+	if err != nil {
+		// The answers are rewritten if they can't be read.
+		f = &file{}
+	}
+	if f.Shared == nil {
+		f.Shared = map[string]any{}
+	}
+	if f.Packages == nil {
+		f.Packages = schema.Values{}
+	}
+
+	for pkg, fields := range vals {
+		for name, val := range fields {
+			if schema.IsShared(name) {
+				f.Shared[name] = val
+				continue
+			}
+
+			if f.Packages[pkg] == nil {
+				f.Packages[pkg] = map[string]any{}
+			}
+			f.Packages[pkg][name] = val
+		}
+	}
+
+	b, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(fp), fileutil.PrivateDirMode)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(fp, b, fileutil.PrivateFileMode)
+	if err != nil {
+		return err
+	}
+
+	// The mode of existing files isn't changed by os.WriteFile.
+	return os.Chmod(fp, fileutil.PrivateFileMode)
+}
+
+// FromEntries returns the answers of the entries of each package.
+func FromEntries(entries map[string][]schema.Entry) schema.Values {
+	out := make(schema.Values, len(entries))
+	for pkg, v := range entries {
+		vals := make(map[string]any, len(v))
+		for _, e := range v {
+			vals[e.FieldName()] = e.Value().Any()
+		}
+		out[pkg] = vals
+	}
+	return out
+}
+
+func read(fp string) (*file, error) {
+	b, err := os.ReadFile(fp)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &file{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	out := new(file)
+	err = yaml.Unmarshal(b, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
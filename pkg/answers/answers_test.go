@@ -0,0 +1,123 @@
+package answers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skiff-sh/config/ptr"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
+
+	"github.com/skiff-sh/skiff/pkg/fields"
+	"github.com/skiff-sh/skiff/pkg/schema"
+	"github.com/skiff-sh/skiff/pkg/settings"
+)
+
+type AnswersTestSuite struct {
+	suite.Suite
+}
+
+func (a *AnswersTestSuite) TestLoad() {
+	type test struct {
+		Given    string
+		Expected schema.Values
+	}
+
+	sch, err := schema.NewSchema(&v1alpha1.Schema{Fields: []*v1alpha1.Field{
+		{Name: "name", Type: ptr.Ptr(v1alpha1.Field_string)},
+		{Name: "port", Type: ptr.Ptr(v1alpha1.Field_number)},
+		{Name: "method", Type: ptr.Ptr(v1alpha1.Field_string), Enum: fields.NewListValue("GET", "POST")},
+		{Name: "project.module", Type: ptr.Ptr(v1alpha1.Field_string)},
+	}})
+	a.Require().NoError(err)
+	other, err := schema.NewSchema(&v1alpha1.Schema{Fields: []*v1alpha1.Field{
+		{Name: "project.module", Type: ptr.Ptr(v1alpha1.Field_string)},
+	}})
+	a.Require().NoError(err)
+	pkgFields := map[string][]*schema.Field{"pkg": sch.Fields, "other": other.Fields}
+
+	tests := map[string]test{
+		"valid": {
+			Given:    "packages:\n  pkg:\n    name: api\n    port: 8080\n    method: GET\n",
+			Expected: schema.Values{"pkg": {"name": "api", "port": 8080.0, "method": "GET"}},
+		},
+		"invalid answers are dropped": {
+			Given: "packages:\n  pkg:\n    name: api\n    port: abc\n    method: PUT\n    removed: a\n" +
+				"  other:\n    name: a\n  unknown:\n    name: a\n",
+			Expected: schema.Values{"pkg": {"name": "api"}},
+		},
+		"shared answers are loaded for every package": {
+			Given:    "shared:\n  project.module: mod\npackages:\n  pkg:\n    project.module: ignored\n",
+			Expected: schema.Values{"pkg": {"project.module": "mod"}, "other": {"project.module": "mod"}},
+		},
+		"no answers": {
+			Expected: schema.Values{},
+		},
+	}
+
+	for desc, t := range tests {
+		a.Run(desc, func() {
+			fp := filepath.Join(a.T().TempDir(), "answers.yaml")
+			if t.Given != "" {
+				a.Require().NoError(os.WriteFile(fp, []byte(t.Given), 0o600))
+			}
+
+			actual, err := Load(fp, pkgFields)
+			if a.NoError(err) {
+				a.Equal(t.Expected, actual)
+			}
+		})
+	}
+}
+
+func (a *AnswersTestSuite) TestSave() {
+	fp := filepath.Join(a.T().TempDir(), DirName, "answers.yaml")
+
+	a.Require().NoError(Save(fp, schema.Values{
+		"a": {"name": "a", "port": 1.0, "project.module": "first"},
+		"b": {"name": "b"},
+	}))
+	a.Require().NoError(Save(fp, schema.Values{
+		"a": {"port": 2.0, "tags": []string{"x"}},
+		"b": {"project.module": "second"},
+	}))
+
+	actual, err := read(fp)
+	if a.NoError(err) {
+		a.Equal(&file{
+			Shared: map[string]any{"project.module": "second"},
+			Packages: schema.Values{
+				"a": {"name": "a", "port": 2, "tags": []any{"x"}},
+				"b": {"name": "b"},
+			},
+		}, actual)
+	}
+}
+
+func (a *AnswersTestSuite) TestPath() {
+	oldBuildDir := settings.BuildDirFunc
+	buildDir := a.T().TempDir()
+	settings.BuildDirFunc = func() (string, error) {
+		return buildDir, nil
+	}
+	defer func() {
+		settings.BuildDirFunc = oldBuildDir
+	}()
+
+	first, err := Path("/a/b")
+	a.Require().NoError(err)
+	second, err := Path("/a/b/")
+	a.Require().NoError(err)
+	other, err := Path("/a/c")
+	a.Require().NoError(err)
+
+	a.Equal(filepath.Join(buildDir, DirName), filepath.Dir(first))
+	a.Equal(first, second)
+	a.NotEqual(first, other)
+}
+
+func TestAnswersTestSuite(t *testing.T) {
+	suite.Run(t, new(AnswersTestSuite))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"slices"
 	"strings"
 	"time"
@@ -12,8 +13,11 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
 	"github.com/urfave/cli/v3"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/skiff-sh/skiff/pkg/accesscontrol"
+	"github.com/skiff-sh/skiff/pkg/answers"
+	"github.com/skiff-sh/skiff/pkg/collection"
 	"github.com/skiff-sh/skiff/pkg/filesystem"
 	"github.com/skiff-sh/skiff/pkg/interact"
 	"github.com/skiff-sh/skiff/pkg/plugin"
@@ -32,7 +36,7 @@ var AddArgPackages = &cli.StringArgs{
 
 var AddFlagNonInteractive = &cli.BoolFlag{
 	Name:    "non-interactive",
-	Usage:   "Disable all form prompts. All package schema's will be required via flags.",
+	Usage:   "Disable all form prompts. All package schema's will be required via flags or values files.",
 	Aliases: []string{"noi", "non-i"},
 }
 
@@ -48,6 +52,37 @@ var AddFlagRoot = &cli.StringFlag{
 	Aliases: []string{"r"},
 }
 
+var AddFlagValues = &cli.StringSliceFlag{
+	Name: "values",
+	Usage: "YAML or JSON file of field values keyed by package name. Can be repeated with later files taking " +
+		"precedence. Flags take precedence over values files.",
+	Aliases: []string{"f"},
+}
+
+var AddFlagResetAnswers = &cli.BoolFlag{
+	Name: "reset-answers",
+	Usage: "Ignore the answers given the last time packages were added to this project. Answers typed into forms " +
+		"are remembered per project and stored unencrypted on your machine.",
+}
+
+var AddFlagPluginTimeout = &cli.DurationFlag{
+	Name:  "plugin-timeout",
+	Usage: "The max time a plugin has to write each file. 0 disables the limit.",
+	Value: plugin.DefaultRequestTimeout,
+}
+
+var AddFlagPluginMemory = &cli.Uint32Flag{
+	Name:  "plugin-memory",
+	Usage: "The max memory of each plugin in MiB.",
+	Value: plugin.DefaultMemoryLimitPages * plugin.MemoryPageSize >> 20,
+	Validator: func(v uint32) error {
+		if maxMB := uint32(plugin.MaxMemoryLimitPages * plugin.MemoryPageSize >> 20); v == 0 || v > maxMB {
+			return fmt.Errorf("must be between 1 and %d", maxMB)
+		}
+		return nil
+	},
+}
+
 var AddFlagPermissions = &cli.StringSliceFlag{
 	Name: "permission",
 	Usage: "Grant permissions for plugins running on your machine. By default, none are granted. Valid permissions are:\n" + strings.Join(
@@ -104,8 +139,13 @@ func LoadPackages(ctx context.Context, packages []string) ([]*v1alpha1.Package,
 	return generators, nil
 }
 
-func FlagsFromPackages(nonInteractive bool, pkgs []*v1alpha1.Package) (map[string][]*schema.Flag, error) {
+// FlagsFromPackages creates the flags for every package keyed by package name. Shared fields (see schema.IsShared)
+// result in a single flag that is present in the list of every package that declares it. Fields whose data, flag or
+// env var would collide with another's are an error.
+func FlagsFromPackages(pkgs []*v1alpha1.Package) (map[string][]*schema.Flag, error) {
 	out := make(map[string][]*schema.Flag, len(pkgs))
+	shared := map[string]*schema.Flag{}
+	var unshared []*schema.Flag
 	for _, pkg := range pkgs {
 		sc, err := schema.NewSchema(pkg.GetSchema())
 		if err != nil {
@@ -113,45 +153,172 @@ func FlagsFromPackages(nonInteractive bool, pkgs []*v1alpha1.Package) (map[strin
 		}
 		flags := make([]*schema.Flag, 0, len(pkgs))
 		for _, field := range sc.Fields {
+			name := field.Proto.GetName()
+			if existing := shared[name]; existing != nil {
+				if !sameDefinition(existing.Field.Proto, field.Proto) {
+					return nil, fmt.Errorf(
+						"package %s: shared field %s has a different type or enum than in package %s",
+						pkg.GetName(),
+						name,
+						existing.Package,
+					)
+				}
+				flags = append(flags, existing)
+				continue
+			}
+
 			fl := schema.FieldToCLIFlag(field)
 			if fl == nil {
-				return nil, fmt.Errorf("package %s: invalid flag %s", pkg.GetName(), field.Proto.GetName())
+				return nil, fmt.Errorf("package %s: invalid flag %s", pkg.GetName(), name)
 			}
 
 			fl.Package = pkg.GetName()
-			namespaced := pkg.GetName() + "." + fl.Accessor.Name()
-			fl.Accessor.SetCategory(fmt.Sprintf("%s flags", pkg.GetName()))
-			// Names must be namespaces to avoid conflicts.
-			fl.Accessor.SetName(namespaced)
-			if nonInteractive {
-				fl.Accessor.SetRequired(true)
+			if schema.IsShared(name) {
+				fl.Accessor.SetCategory("shared flags")
+				shared[name] = fl
+			} else {
+				fl.Accessor.SetCategory(fmt.Sprintf("%s flags", pkg.GetName()))
+				// Names must be namespaces to avoid conflicts.
+				fl.Accessor.SetName(pkg.GetName() + "." + name)
+				unshared = append(unshared, fl)
 			}
+			fl.Accessor.SetSources(cli.EnvVars(EnvVarName(pkg.GetName(), name)))
 			flags = append(flags, fl)
 		}
 		out[pkg.GetName()] = flags
 	}
 
+	err := checkSharedCollisions(shared, unshared)
+	if err != nil {
+		return nil, err
+	}
+
 	return out, nil
 }
 
+// checkSharedCollisions returns an error if a shared field can't be told apart from a package field. Package fields
+// can't be named by the namespace of a shared field since the shared field is nested within it e.g. project and
+// project.module. Flags and env vars of different fields can't be the same e.g. the field module of package project
+// and project.module.
+func checkSharedCollisions(shared map[string]*schema.Flag, unshared []*schema.Flag) error {
+	namespaces := map[string]string{}
+	for name := range shared {
+		ns, _, _ := strings.Cut(name, ".")
+		namespaces[ns] = name
+	}
+
+	envVars := map[string]*schema.Flag{}
+	for _, v := range shared {
+		envVars[EnvVarName(v.Package, v.FieldName())] = v
+	}
+
+	for _, v := range unshared {
+		name := v.FieldName()
+		if sharedName, ok := namespaces[name]; ok {
+			return fmt.Errorf("package %s: field %s collides with shared field %s", v.Package, name, sharedName)
+		}
+
+		if other := shared[v.Accessor.Name()]; other != nil {
+			return fmt.Errorf(
+				"package %s: flag --%s collides with shared field %s",
+				v.Package,
+				v.Accessor.Name(),
+				other.FieldName(),
+			)
+		}
+
+		env := EnvVarName(v.Package, name)
+		if other := envVars[env]; other != nil {
+			return fmt.Errorf(
+				"package %s: env var %s of field %s collides with --%s",
+				v.Package,
+				env,
+				name,
+				other.Accessor.Name(),
+			)
+		}
+		envVars[env] = v
+	}
+
+	return nil
+}
+
+// EnvVarName returns the environment variable for a package field e.g. create-http-route and name becomes
+// SKIFF_CREATE_HTTP_ROUTE_NAME. Shared fields aren't namespaced e.g. project.module becomes SKIFF_PROJECT_MODULE.
+func EnvVarName(pkg, field string) string {
+	if schema.IsShared(field) {
+		return "SKIFF_" + tmpl.ScreamingSnakeCase(field)
+	}
+	return "SKIFF_" + tmpl.ScreamingSnakeCase(pkg) + "_" + tmpl.ScreamingSnakeCase(field)
+}
+
+// sameDefinition whether a shared field is declared the same way by two packages. The flag, form and validation of
+// the first package's field are used for every package so the values it accepts must be the same.
+func sameDefinition(a, b *v1alpha1.Field) bool {
+	return a.GetType() == b.GetType() && a.GetItems().GetType() == b.GetItems().GetType() &&
+		proto.Equal(a.GetEnum(), b.GetEnum()) && proto.Equal(a.GetItems().GetEnum(), b.GetItems().GetEnum())
+}
+
 type AddArgs struct {
-	ProjectRoot  filesystem.Filesystem
-	CreateAll    bool
-	GrantedPerms []v1alpha1.PackagePermissions_Plugin
+	ProjectRoot    filesystem.Filesystem
+	CreateAll      bool
+	NonInteractive bool
+	GrantedPerms   []v1alpha1.PackagePermissions_Plugin
+	// Paths to values files.
+	ValuesFiles []string
+	// Ignore previous answers.
+	ResetAnswers bool
+	// Limits of every plugin.
+	PluginTimeout  time.Duration
+	PluginMemoryMB uint32
 }
 
-func (a *AddAction) Act(ctx context.Context, args *AddArgs) error {
+func (a *AddAction) Act(ctx context.Context, args *AddArgs) (err error) {
 	pkgs := a.Packages
 	pkgFlags := a.PackageFlags
+
+	pkgFields := packageFields(pkgFlags)
+	values, err := loadValues(args.ValuesFiles, pkgFields)
+	if err != nil {
+		return err
+	}
+
+	root, err := args.ProjectRoot.Abs(".")
+	if err != nil {
+		return err
+	}
+
+	answersPath, err := answers.Path(root)
+	if err != nil {
+		return err
+	}
+
+	remembered := schema.Values{}
+	if !args.ResetAnswers {
+		remembered, err = answers.Load(answersPath, pkgFields)
+		if err != nil {
+			interact.Errorf("Failed to load previous answers: %s", err.Error())
+			remembered = schema.Values{}
+		}
+	}
+
 	pkgSystems := map[string]system.System{}
 	granter := accesscontrol.NewTerminalGranter()
-	mediator := system.NewMediator()
+	mediator := system.NewMediator(root, system.DefaultContextProviders()...)
 
 	var removeIdx []int
 	for i, pkg := range pkgs {
 		policy := accesscontrol.NewPluginAccessPolicy(args.GrantedPerms)
 		needed := policy.Diff(pkg.GetPermissions().GetPlugin()...)
-		if len(needed) > 0 && !granter.RequestAccess(ctx, pkg.GetName(), needed) {
+		granted := true
+		if len(needed) > 0 {
+			granted, err = granter.RequestAccess(ctx, pkg.GetName(), needed)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !granted {
 			removeIdx = append(removeIdx, i)
 		} else {
 			policy.Grant(needed...)
@@ -172,48 +339,91 @@ func (a *AddAction) Act(ctx context.Context, args *AddArgs) error {
 	data := schema.NewDataSource()
 
 	missingPackageFlags := map[string][]*schema.Flag{}
-	for packageName, flags := range pkgFlags {
-		for i := range flags {
-			if flags[i].Flag.IsSet() {
-				data.AddPackageEntry(packageName, flags[i])
-			} else {
-				missingPackageFlags[packageName] = append(missingPackageFlags[packageName], flags[i])
+	var missingShared []*schema.Flag
+	var missingNames []string
+	// Packages are visited in the order given so the first package declaring a shared field always provides it.
+	for _, pkg := range pkgs {
+		packageName := pkg.GetName()
+		for _, fl := range pkgFlags[packageName] {
+			shared := schema.IsShared(fl.FieldName())
+			if shared && (data.HasSharedEntry(fl.FieldName()) || slices.Contains(missingShared, fl)) {
+				continue
+			}
+
+			var e schema.Entry
+			valuesEntry, inValues := values.Entry(packageName, fl.Field)
+			answer, answered := remembered.Entry(packageName, fl.Field)
+			switch {
+			case fl.Flag.IsSet():
+				e = fl
+			case inValues:
+				e = valuesEntry
+			case args.NonInteractive && answered:
+				e = answer
+			case args.NonInteractive && fl.Field.Default != nil:
+				e = fl
+			}
+
+			switch {
+			case e == nil && shared:
+				missingShared = append(missingShared, fl)
+				missingNames = append(missingNames, "--"+fl.Accessor.Name())
+			case e == nil:
+				missingPackageFlags[packageName] = append(missingPackageFlags[packageName], fl)
+				missingNames = append(missingNames, "--"+fl.Accessor.Name())
+			case shared:
+				data.AddSharedEntry(e)
+			default:
+				data.AddPackageEntry(packageName, e)
 			}
 		}
 	}
 
-	groups := make([]*huh.Group, 0, len(missingPackageFlags))
-	pkgFormFields := make(map[string][]*schema.FormField, len(missingPackageFlags))
-	for packageName, flags := range missingPackageFlags {
-		formFields := make([]*schema.FormField, 0, len(flags))
-		for _, fl := range flags {
-			ff := schema.NewFormField(fl.Field)
-			if ff == nil {
-				return errors.New("failed to create field")
-			}
+	if args.NonInteractive && len(missingNames) > 0 {
+		slices.Sort(missingNames)
+		return fmt.Errorf("%w: missing values for %s", ErrSchema, strings.Join(missingNames, ", "))
+	}
 
-			ff.Accessor.SetDescription(
-				strings.Join([]string{fl.Field.Proto.GetDescription(), ff.Accessor.Description()}, ". "),
-			)
-			ff.Accessor.SetTitle(fl.Field.Proto.GetName())
-			formFields = append(formFields, ff)
+	groups := make([]*huh.Group, 0, len(missingPackageFlags)+1)
+	sharedFormFields, err := newFormFields(missingShared, remembered)
+	if err != nil {
+		return err
+	}
+	if len(sharedFormFields) > 0 {
+		group := interact.NewHuhGroup(schema.FlattenHuhFields(sharedFormFields)...)
+		group.Title("Shared").Description("Used by multiple packages.")
+		groups = append(groups, group)
+	}
+
+	pkgFormFields := make(map[string][]*schema.FormField, len(missingPackageFlags))
+	for _, pkg := range pkgs {
+		flags, ok := missingPackageFlags[pkg.GetName()]
+		if !ok {
+			continue
 		}
 
-		pkg := pkgs[slices.IndexFunc(pkgs, func(p *v1alpha1.Package) bool {
-			return p.GetName() == packageName
-		})]
+		formFields, err := newFormFields(flags, remembered)
+		if err != nil {
+			return err
+		}
 
-		pkgFormFields[packageName] = formFields
+		pkgFormFields[pkg.GetName()] = formFields
 
 		group := interact.NewHuhGroup(schema.FlattenHuhFields(formFields)...)
 		group.Title(fmt.Sprintf("Package %s", pkg.GetName())).Description(pkg.GetDescription())
 		groups = append(groups, group)
 	}
 
-	form := interact.NewHuhForm(groups...)
-	err := interact.DefaultFormRunner(ctx, form)
-	if err != nil {
-		return err
+	if len(groups) > 0 {
+		form := interact.NewHuhForm(groups...)
+		err = interact.DefaultFormRunner(ctx, form)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range sharedFormFields {
+		data.AddSharedEntry(v)
 	}
 
 	for pkgName, inputs := range pkgFormFields {
@@ -222,6 +432,26 @@ func (a *AddAction) Act(ctx context.Context, args *AddArgs) error {
 		}
 	}
 
+	// Only answers typed into the form are remembered. Values from flags, env vars and values files are often secrets
+	// injected by CI.
+	typed := make(map[string][]schema.Entry, len(pkgFormFields)+1)
+	for i, v := range sharedFormFields {
+		pkgName := missingShared[i].Package
+		typed[pkgName] = append(typed[pkgName], v)
+	}
+	for pkgName, inputs := range pkgFormFields {
+		for _, v := range inputs {
+			typed[pkgName] = append(typed[pkgName], v)
+		}
+	}
+
+	if len(typed) > 0 {
+		err = answers.Save(answersPath, answers.FromEntries(typed))
+		if err != nil {
+			interact.Errorf("Failed to save answers: %s", err.Error())
+		}
+	}
+
 	confirmer := func(ctx context.Context, f *registry.File) (bool, error) {
 		var prompt string
 		if args.ProjectRoot.Exists(f.Path) {
@@ -229,10 +459,9 @@ func (a *AddAction) Act(ctx context.Context, args *AddArgs) error {
 		} else {
 			prompt = fmt.Sprintf("Create file %s", f.Path)
 		}
-		conf := interact.Confirm(ctx, func(c *huh.Confirm) *huh.Confirm {
+		return interact.Confirm(ctx, func(c *huh.Confirm) *huh.Confirm {
 			return c.Title(prompt)
 		})
-		return conf, nil
 	}
 	if args.CreateAll {
 		confirmer = func(_ context.Context, _ *registry.File) (bool, error) {
@@ -240,15 +469,32 @@ func (a *AddAction) Act(ctx context.Context, args *AddArgs) error {
 		}
 	}
 
-	compiler, err := plugin.NewWazeroCompiler()
+	compiler, err := newPluginCompiler(
+		ctx,
+		plugin.WithRequestTimeout(args.PluginTimeout),
+		// Running plugins are aborted when cancelled e.g. by Ctrl-C.
+		plugin.WithInterruptible(ctx.Done() != nil),
+		plugin.WithMemoryLimitPages(args.PluginMemoryMB*(1<<20/plugin.MemoryPageSize)),
+	)
 	if err != nil {
 		return err
 	}
 
 	tmplFact := tmpl.NewGoFactory()
 
+	// Files written before failing or being cancelled are rolled back so the project isn't left half generated.
+	changes := registry.NewChangeset(args.ProjectRoot)
+	defer func() {
+		if err != nil {
+			err = rollback(changes, err)
+		}
+	}()
+
 	for _, v := range pkgs {
 		data := data.Package(v.GetName())
+		for k, val := range pkgSystems[v.GetName()].Context() {
+			data.AddEntry(schema.NewContextEntry(k, val))
+		}
 
 		gen, err := registry.NewPackageGenerator(ctx, compiler, pkgSystems[v.GetName()], tmplFact, v)
 		if err != nil {
@@ -257,10 +503,14 @@ func (a *AddAction) Act(ctx context.Context, args *AddArgs) error {
 
 		pkg, err := gen.Generate(ctx, data)
 		if err != nil {
-			return err
+			return fmt.Errorf("package %s: %w", v.GetName(), err)
 		}
 
 		for _, fi := range pkg.Files {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
 			ok, err := confirmer(ctx, fi)
 			if err != nil {
 				return err
@@ -270,9 +520,9 @@ func (a *AddAction) Act(ctx context.Context, args *AddArgs) error {
 				continue
 			}
 
-			err = fi.WriteTo(args.ProjectRoot)
+			err = changes.Write(fi)
 			if err != nil {
-				interact.Errorf("Failed to write file %s: %s", fi.Path, err.Error())
+				return fmt.Errorf("failed to write file %s: %w", fi.Path, err)
 			}
 		}
 	}
@@ -280,6 +530,91 @@ func (a *AddAction) Act(ctx context.Context, args *AddArgs) error {
 	return nil
 }
 
+// rollback rolls back the changes made before failing or being cancelled with cause.
+func rollback(changes *registry.Changeset, cause error) error {
+	written := len(changes.Changes)
+	if written == 0 {
+		return cause
+	}
+
+	err := changes.Rollback()
+	if err != nil {
+		// Not reported as a cancellation since the project needs fixing.
+		return fmt.Errorf("%s, some written files remain:\n%w", cause, err)
+	}
+
+	interact.Warnf("Rolled back %d written files.", written)
+	return cause
+}
+
+// newFormFields creates the form fields for the flags. Previous answers are pre-filled.
+func newFormFields(flags []*schema.Flag, remembered schema.Values) ([]*schema.FormField, error) {
+	out := make([]*schema.FormField, 0, len(flags))
+	for _, fl := range flags {
+		field := fl.Field
+		if answer, ok := remembered.Entry(fl.Package, field); ok {
+			field = field.WithDefault(answer.Value().Any())
+		}
+
+		ff := schema.NewFormField(field)
+		if ff == nil {
+			return nil, errors.New("failed to create field")
+		}
+
+		ff.Accessor.SetDescription(
+			strings.Join([]string{fl.Field.Proto.GetDescription(), ff.Accessor.Description()}, ". "),
+		)
+		ff.Accessor.SetTitle(fl.Field.Proto.GetName())
+		out = append(out, ff)
+	}
+	return out, nil
+}
+
+func packageFields(pkgFlags map[string][]*schema.Flag) map[string][]*schema.Field {
+	out := make(map[string][]*schema.Field, len(pkgFlags))
+	for pkg, flags := range pkgFlags {
+		out[pkg] = collection.Map(flags, func(e *schema.Flag) *schema.Field {
+			return e.Field
+		})
+	}
+	return out
+}
+
+// loadValues loads and merges all values files in order. Every value is validated against the schema of its package.
+func loadValues(paths []string, pkgFields map[string][]*schema.Field) (schema.Values, error) {
+	out := schema.Values{}
+	if len(paths) == 0 {
+		return out, nil
+	}
+
+	for _, v := range paths {
+		b, err := os.ReadFile(v)
+		if err != nil {
+			return nil, err
+		}
+
+		vals, err := schema.ParseValues(b)
+		if err != nil {
+			return nil, fmt.Errorf("values file %s: %w", v, err)
+		}
+
+		err = vals.Resolve(pkgFields)
+		if err != nil {
+			return nil, fmt.Errorf("values file %s:\n%w", v, err)
+		}
+
+		out.Merge(vals)
+	}
+
+	// Only one value can be used for a shared field regardless of the package it's given under.
+	err := out.CheckShared()
+	if err != nil {
+		return nil, fmt.Errorf("values files:\n%w", err)
+	}
+
+	return out, nil
+}
+
 func initLoader(pa string) registry.Loader {
 	if registry.IsHTTPPath(pa) {
 		cl := &http.Client{
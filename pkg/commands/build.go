@@ -130,7 +130,7 @@ func newPluginTools(ctx context.Context) *buildTools {
 		return out
 	}
 
-	out.PluginCompiler, err = plugin.NewWazeroCompiler()
+	out.PluginCompiler, err = newPluginCompiler(ctx)
 	if err != nil {
 		out.Err = fmt.Errorf("failed to create WASM compiler: %w", err)
 		return out
@@ -203,10 +203,12 @@ func hydratePlugin(ctx context.Context, tools *buildTools, absPath string, buff
 		return nil, err
 	}
 
-	_, err = tools.PluginCompiler.Compile(ctx, buff.Bytes(), plugin.CompileOpts{})
+	// Only compiled to check the plugin is valid. The compiled plugin is cached for when it's added.
+	plug, err := tools.PluginCompiler.Compile(ctx, buff.Bytes(), plugin.CompileOpts{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile plugin %s: %w", absPath, err)
 	}
+	_ = plug.Close()
 
 	out := make([]byte, len(buff.Bytes()))
 	copy(out, buff.Bytes())
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/eddieowens/opts"
+	"github.com/urfave/cli/v3"
+
+	"github.com/skiff-sh/skiff/pkg/interact"
+	"github.com/skiff-sh/skiff/pkg/plugin"
+)
+
+func newCacheCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Inspect or clear the compiled plugin cache.",
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List the compiled plugins in the cache.",
+				Action: func(_ context.Context, command *cli.Command) error {
+					dir, err := plugin.CacheDir()
+					if err != nil {
+						return err
+					}
+
+					entries, err := plugin.ListCache(dir)
+					if err != nil {
+						return fmt.Errorf("failed to list cache: %w", err)
+					}
+
+					w := command.Root().Writer
+					var total int64
+					for _, v := range entries {
+						total += v.Size
+						rel, _ := filepath.Rel(dir, v.Path)
+						_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", v.ModTime.Format(time.DateTime), formatBytes(v.Size), rel)
+					}
+					_, _ = fmt.Fprintf(w, "%d compiled plugins (%s) in %s\n", len(entries), formatBytes(total), dir)
+					return nil
+				},
+			},
+			{
+				Name:  "clear",
+				Usage: "Remove every compiled plugin from the cache.",
+				Action: func(_ context.Context, _ *cli.Command) error {
+					dir, err := plugin.CacheDir()
+					if err != nil {
+						return err
+					}
+
+					err = plugin.ClearCache(dir)
+					if err != nil {
+						return fmt.Errorf("failed to clear cache: %w", err)
+					}
+					interact.Successf("Cleared %s", dir)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// newPluginCompiler creates a compiler which caches compiled plugins. The cache is evicted down to its max size whenever
+// a plugin is added to it. If the cache can't be used, plugins are compiled on every run.
+func newPluginCompiler(ctx context.Context, op ...opts.Opt[plugin.CompilerOpts]) (plugin.Compiler, error) {
+	dir, err := plugin.CacheDir()
+	if err != nil {
+		slog.DebugContext(ctx, "Plugin cache disabled.", "err", err.Error())
+		return plugin.NewWazeroCompiler(op...)
+	}
+
+	compiler, err := plugin.NewWazeroCompiler(append(op, plugin.WithCacheDir(dir))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &evictingCompiler{Compiler: compiler, Dir: dir}, nil
+}
+
+var _ plugin.Compiler = (*evictingCompiler)(nil)
+
+// evictingCompiler evicts the plugin cache within Dir after a plugin is added to it.
+type evictingCompiler struct {
+	Compiler plugin.Compiler
+	Dir      string
+}
+
+func (e *evictingCompiler) Compile(ctx context.Context, b []byte, o plugin.CompileOpts) (plugin.Plugin, error) {
+	before := plugin.CacheModTime(e.Dir)
+	plug, err := e.Compiler.Compile(ctx, b, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache hits don't add entries so the cache is only walked when it grew.
+	if plugin.CacheModTime(e.Dir).Equal(before) {
+		return plug, nil
+	}
+
+	removed, err := plugin.EvictCache(e.Dir, plugin.DefaultCacheMaxBytes)
+	if err != nil {
+		slog.DebugContext(ctx, "Failed to evict plugin cache.", "err", err.Error())
+	}
+	if len(removed) > 0 {
+		slog.DebugContext(ctx, "Evicted plugin cache.", "count", len(removed))
+	}
+
+	return plug, nil
+}
+
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -124,6 +124,7 @@ Global options:{{template "visiblePersistentFlagTemplate" .}}{{end}}
 					})
 				},
 			},
+			newCacheCmd(),
 		},
 	}
 
@@ -141,9 +142,32 @@ func (r *RootCommand) Run(ctx context.Context, args []string) error {
 
 	r.CLI.Commands = append(r.CLI.Commands, addCmd)
 
+	if argsHaveFlag(args, cli.HelpFlag) {
+		// Packages would otherwise be treated as help topics. The package flags have already been added.
+		args = removeAddPackageArgs(args, addCmd.Flags)
+	}
+
 	return r.CLI.Run(ctx, args)
 }
 
+func removeAddPackageArgs(args []string, flags []cli.Flag) []string {
+	argIdxs := argIndices(args, flags)
+	//nolint:mnd // not magic
+	if len(argIdxs) <= 2 || args[argIdxs[1]] != "add" {
+		return args
+	}
+
+	// Removed by position since a flag's value can be the same as a package.
+	pkgIdxs := argIdxs[2:]
+	out := make([]string, 0, len(args)-len(pkgIdxs))
+	for i, v := range args {
+		if !slices.Contains(pkgIdxs, i) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func newAddCmd(ctx context.Context, args []string) (*cli.Command, error) {
 	addCmd := &cli.Command{
 		Name:  "add",
@@ -153,6 +177,10 @@ func newAddCmd(ctx context.Context, args []string) (*cli.Command, error) {
 			AddFlagCreateAll,
 			AddFlagRoot,
 			AddFlagPermissions,
+			AddFlagValues,
+			AddFlagResetAnswers,
+			AddFlagPluginTimeout,
+			AddFlagPluginMemory,
 		},
 		Arguments: []cli.Argument{
 			AddArgPackages,
@@ -184,7 +212,9 @@ func newAddCmd(ctx context.Context, args []string) (*cli.Command, error) {
 		return nil, err
 	}
 
-	flags, err := FlagsFromPackages(argsHaveFlag(args, AddFlagNonInteractive), pkgs)
+	// Flags aren't required since values files, env vars, previous answers and defaults can provide any field. Missing
+	// values are reported by AddAction.Act.
+	flags, err := FlagsFromPackages(pkgs)
 	if err != nil {
 		return nil, err
 	}
@@ -193,7 +223,10 @@ func newAddCmd(ctx context.Context, args []string) (*cli.Command, error) {
 	flatFlags := make([]cli.Flag, 0, len(act.PackageFlags))
 	for _, pkgFlags := range flags {
 		for i := range pkgFlags {
-			flatFlags = append(flatFlags, pkgFlags[i].Flag)
+			// Shared flags are present for every package that declares them.
+			if !slices.Contains(flatFlags, pkgFlags[i].Flag) {
+				flatFlags = append(flatFlags, pkgFlags[i].Flag)
+			}
 		}
 	}
 	addCmd.Flags = append(addCmd.Flags, flatFlags...)
@@ -210,8 +243,13 @@ func newAddCmd(ctx context.Context, args []string) (*cli.Command, error) {
 		perms := command.StringSlice(AddFlagPermissions.Name)
 
 		err := act.Act(ctx, &AddArgs{
-			ProjectRoot: filesystem.New(root),
-			CreateAll:   command.Bool(AddFlagCreateAll.Name),
+			ProjectRoot:    filesystem.New(root),
+			CreateAll:      command.Bool(AddFlagCreateAll.Name),
+			NonInteractive: command.Bool(AddFlagNonInteractive.Name),
+			ValuesFiles:    command.StringSlice(AddFlagValues.Name),
+			ResetAnswers:   command.Bool(AddFlagResetAnswers.Name),
+			PluginTimeout:  command.Duration(AddFlagPluginTimeout.Name),
+			PluginMemoryMB: command.Uint32(AddFlagPluginMemory.Name),
 			GrantedPerms: collection.Map(perms, func(e string) v1alpha1.PackagePermissions_Plugin {
 				return v1alpha1.PackagePermissions_Plugin(v1alpha1.PackagePermissions_Plugin_value[e])
 			}),
@@ -229,10 +267,20 @@ func newAddCmd(ctx context.Context, args []string) (*cli.Command, error) {
 }
 
 func filterFlagsFromArgs(s []string, possibleFlags []cli.Flag) []string {
+	idxs := argIndices(s, possibleFlags)
+	out := make([]string, 0, len(idxs))
+	for _, v := range idxs {
+		out = append(out, s[v])
+	}
+	return out
+}
+
+// argIndices returns the indices of the args in s which aren't flags or the values of flags.
+func argIndices(s []string, possibleFlags []cli.Flag) []int {
 	possibleFlags = append(possibleFlags, cli.HelpFlag, cli.VersionFlag)
 	skipNext := false
-	out := make([]string, 0, len(s))
-	for _, v := range s {
+	out := make([]int, 0, len(s))
+	for i, v := range s {
 		if skipNext {
 			skipNext = false
 			continue
@@ -252,15 +300,20 @@ func filterFlagsFromArgs(s []string, possibleFlags []cli.Flag) []string {
 			continue
 		}
 
-		out = append(out, v)
+		out = append(out, i)
 	}
 
 	return out
 }
 
+// argsHaveFlag whether fl is set within args either as --flag, --flag value or --flag=value.
 func argsHaveFlag(args []string, fl cli.Flag) bool {
 	names := fl.Names()
 	return slices.ContainsFunc(args, func(s string) bool {
-		return slices.Contains(names, strings.TrimLeft(s, "-"))
+		if !strings.HasPrefix(s, "-") {
+			return false
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(s, "-"), "=")
+		return slices.Contains(names, name)
 	})
 }
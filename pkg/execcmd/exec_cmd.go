@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"context"
 	"log/slog"
+	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/skiff-sh/skiff/pkg/bufferpool"
 	"github.com/skiff-sh/skiff/pkg/system"
 )
 
+// How long a cancelled command has to exit after being interrupted before it's killed.
+const cancelWaitDelay = 5 * time.Second
+
 var (
 	// DefaultRunner is the default Runner for the package.
 	DefaultRunner Runner = RunnerFunc(func(cmd *Cmd) error {
@@ -41,6 +46,14 @@ func NewCmd(ctx context.Context, name string, args ...string) (*Cmd, error) {
 	if cmd.Err != nil {
 		return nil, cmd.Err
 	}
+	// Interrupt rather than kill so the command can clean up e.g. temp dirs. Interrupts aren't supported on Windows.
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = cancelWaitDelay
 
 	var err error
 	cmd.Dir, err = system.Getwd()
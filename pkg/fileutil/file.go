@@ -14,6 +14,10 @@ import (
 const (
 	DefaultFileMode = 0o644
 	DefaultDirMode  = 0o755
+
+	// Modes of files only readable by their owner.
+	PrivateFileMode = 0o600
+	PrivateDirMode  = 0o700
 )
 
 // FindSibling recursively searches upwards from the "from" parameter until a sibling file of "target" is found. If the "target"
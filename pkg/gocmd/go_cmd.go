@@ -203,5 +203,8 @@ func (g *goCLI) Build(ctx context.Context, build BuildArgs) (*execcmd.Buffers, e
 	}
 
 	err = execcmd.Run(cmd)
+	if err != nil && ctx.Err() != nil {
+		return cmd.Buffers, fmt.Errorf("go build interrupted: %w", ctx.Err())
+	}
 	return cmd.Buffers, err
 }
@@ -2,14 +2,27 @@ package interact
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/x/term"
 )
 
+// ErrCancelled returned when the user cancels a form e.g. via Ctrl-C.
+var ErrCancelled = errors.New("cancelled")
+
 var DefaultFormRunner = func(ctx context.Context, f *huh.Form) error {
-	return f.RunWithContext(ctx)
+	err := f.RunWithContext(ctx)
+	if errors.Is(err, huh.ErrUserAborted) || (err != nil && ctx.Err() != nil) {
+		return ErrCancelled
+	}
+	return err
+}
+
+// IsCancelled whether err was caused by the user cancelling.
+func IsCancelled(err error) bool {
+	return errors.Is(err, ErrCancelled) || errors.Is(err, context.Canceled)
 }
 
 func NewHuhForm(groups ...*huh.Group) *huh.Form {
@@ -26,11 +39,15 @@ func IsTerminal() bool {
 	return term.IsTerminal(os.Stdin.Fd())
 }
 
-func Confirm(ctx context.Context, fact func(c *huh.Confirm) *huh.Confirm) bool {
+// Confirm asks the user to confirm. Only returns an error if the user cancelled, any other failure is treated as a
+// refusal.
+func Confirm(ctx context.Context, fact func(c *huh.Confirm) *huh.Confirm) (bool, error) {
 	var val bool
 	err := DefaultFormRunner(ctx, NewHuhForm(NewHuhGroup(fact(huh.NewConfirm()).Value(&val))))
-	if err != nil {
-		return false
+	if IsCancelled(err) || ctx.Err() != nil {
+		return false, ErrCancelled
+	} else if err != nil {
+		return false, nil
 	}
-	return val
+	return val, nil
 }
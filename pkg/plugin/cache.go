@@ -0,0 +1,135 @@
+package plugin
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/skiff-sh/skiff/pkg/settings"
+)
+
+const (
+	CacheDirName = "plugincache"
+	// DefaultCacheMaxBytes the size the compiled plugin cache is evicted down to.
+	DefaultCacheMaxBytes int64 = 512 << 20
+)
+
+// CacheDir directory housing the compiled plugin cache. wazero keys every entry by the digest of the WASM and nests
+// entries by the wazero version, OS, and arch.
+func CacheDir() (string, error) {
+	dir, err := settings.BuildDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, CacheDirName), nil
+}
+
+// CacheEntry a compiled plugin within the cache.
+type CacheEntry struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// ListCache returns every entry in the cache dir, oldest first. An entry's age is the time it was compiled since wazero
+// doesn't touch entries when they're used. Returns nothing if dir does not exist.
+func ListCache(dir string) ([]*CacheEntry, error) {
+	var out []*CacheEntry
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		out = append(out, &CacheEntry{
+			Path:    p,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(out, func(a, b *CacheEntry) int {
+		return a.ModTime.Compare(b.ModTime)
+	})
+
+	return out, nil
+}
+
+// EvictCache removes the oldest entries until the cache is at most maxBytes. Eviction is FIFO rather than LRU so a
+// plugin used on every run is still recompiled once enough newer plugins are compiled. Returns the removed entries.
+func EvictCache(dir string, maxBytes int64) ([]*CacheEntry, error) {
+	entries, err := ListCache(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, v := range entries {
+		total += v.Size
+	}
+
+	var removed []*CacheEntry
+	for _, v := range entries {
+		if total <= maxBytes {
+			break
+		}
+
+		err = os.Remove(v.Path)
+		if err != nil {
+			return removed, err
+		}
+		total -= v.Size
+		removed = append(removed, v)
+	}
+
+	return removed, nil
+}
+
+// CacheModTime returns the latest mod time of the cache dir and the dirs within it. wazero nests entries in a dir per
+// version, OS, and arch so the mod time changes whenever an entry is added without walking every entry. Returns the
+// zero time if dir does not exist.
+func CacheModTime(dir string) time.Time {
+	var out time.Time
+	info, err := os.Stat(dir)
+	if err != nil {
+		return out
+	}
+	out = info.ModTime()
+
+	entries, _ := os.ReadDir(dir)
+	for _, v := range entries {
+		if !v.IsDir() {
+			continue
+		}
+
+		info, err := v.Info()
+		if err == nil && info.ModTime().After(out) {
+			out = info.ModTime()
+		}
+	}
+
+	return out
+}
+
+// ClearCache removes every entry in the cache.
+func ClearCache(dir string) error {
+	return os.RemoveAll(dir)
+}
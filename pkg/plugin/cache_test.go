@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheTestSuite struct {
+	suite.Suite
+}
+
+func (c *CacheTestSuite) TestEvictCache() {
+	type test struct {
+		GivenMaxBytes   int64
+		Expected        []string
+		ExpectedRemoved []string
+	}
+
+	tests := map[string]test{
+		"under max": {
+			GivenMaxBytes: 30,
+			Expected:      []string{"old", "mid", "new"},
+		},
+		"oldest are removed": {
+			GivenMaxBytes:   15,
+			Expected:        []string{"new"},
+			ExpectedRemoved: []string{"old", "mid"},
+		},
+		"everything is removed": {
+			GivenMaxBytes:   0,
+			ExpectedRemoved: []string{"old", "mid", "new"},
+		},
+	}
+
+	for desc, t := range tests {
+		c.Run(desc, func() {
+			dir := filepath.Join(c.T().TempDir(), CacheDirName)
+			now := time.Now()
+			for i, name := range []string{"old", "mid", "new"} {
+				fp := filepath.Join(dir, "wazero", name)
+				c.Require().NoError(os.MkdirAll(filepath.Dir(fp), 0o755))
+				c.Require().NoError(os.WriteFile(fp, make([]byte, 10), 0o600))
+				modTime := now.Add(time.Duration(i) * time.Minute)
+				c.Require().NoError(os.Chtimes(fp, modTime, modTime))
+			}
+
+			removed, err := EvictCache(dir, t.GivenMaxBytes)
+			if !c.NoError(err) {
+				return
+			}
+			c.Equal(t.ExpectedRemoved, entryNames(removed))
+
+			entries, err := ListCache(dir)
+			if c.NoError(err) {
+				c.Equal(t.Expected, entryNames(entries))
+			}
+		})
+	}
+}
+
+func (c *CacheTestSuite) TestCacheModTime() {
+	dir := filepath.Join(c.T().TempDir(), CacheDirName)
+	c.True(CacheModTime(dir).IsZero())
+
+	old := time.Now().Add(-time.Hour)
+	sub := filepath.Join(dir, "wazero")
+	c.Require().NoError(os.MkdirAll(sub, 0o755))
+	c.Require().NoError(os.WriteFile(filepath.Join(sub, "old"), make([]byte, 10), 0o600))
+	c.Require().NoError(os.Chtimes(sub, old, old))
+	c.Require().NoError(os.Chtimes(dir, old, old))
+	c.True(CacheModTime(dir).Equal(old))
+
+	_, err := os.ReadFile(filepath.Join(sub, "old"))
+	c.Require().NoError(err)
+	c.True(CacheModTime(dir).Equal(old), "reading an entry doesn't change the mod time")
+
+	c.Require().NoError(os.WriteFile(filepath.Join(sub, "new"), make([]byte, 10), 0o600))
+	c.True(CacheModTime(dir).After(old), "adding an entry changes the mod time")
+}
+
+func (c *CacheTestSuite) TestListCacheNotExist() {
+	entries, err := ListCache(filepath.Join(c.T().TempDir(), "derp"))
+	c.NoError(err)
+	c.Empty(entries)
+}
+
+func entryNames(entries []*CacheEntry) []string {
+	var out []string
+	for _, v := range entries {
+		out = append(out, filepath.Base(v.Path))
+	}
+	return out
+}
+
+func TestCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
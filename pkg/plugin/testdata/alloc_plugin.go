@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/skiff-sh/api/go/skiff/plugin/v1alpha1"
+	"github.com/skiff-sh/sdk-go/skiff"
+)
+
+var _ skiff.Plugin = (*AllocPlugin)(nil)
+
+// AllocPlugin allocates 512 MiB.
+type AllocPlugin struct{}
+
+var sink []byte
+
+func (p *AllocPlugin) WriteFile(_ *skiff.Context, _ *v1alpha1.WriteFileRequest) (*v1alpha1.WriteFileResponse, error) {
+	sink = make([]byte, 512<<20)
+	return &v1alpha1.WriteFileResponse{Contents: sink[:2]}, nil
+}
+
+func init() {
+	skiff.Register(new(AllocPlugin))
+}
+
+func main() {
+}
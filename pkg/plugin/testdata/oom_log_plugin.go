@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+
+	"github.com/skiff-sh/api/go/skiff/plugin/v1alpha1"
+	"github.com/skiff-sh/sdk-go/skiff"
+)
+
+var _ skiff.Plugin = (*OOMLogPlugin)(nil)
+
+// OOMLogPlugin logs an out of memory error and exits without running out of memory.
+type OOMLogPlugin struct{}
+
+func (p *OOMLogPlugin) WriteFile(_ *skiff.Context, _ *v1alpha1.WriteFileRequest) (*v1alpha1.WriteFileResponse, error) {
+	_, _ = os.Stderr.WriteString("upstream: out of memory\n")
+	os.Exit(2)
+	return nil, nil
+}
+
+func init() {
+	skiff.Register(new(OOMLogPlugin))
+}
+
+func main() {
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/skiff-sh/api/go/skiff/plugin/v1alpha1"
+	"github.com/skiff-sh/sdk-go/skiff"
+)
+
+var _ skiff.Plugin = (*SpinPlugin)(nil)
+
+// SpinPlugin never responds.
+type SpinPlugin struct{}
+
+func (p *SpinPlugin) WriteFile(_ *skiff.Context, _ *v1alpha1.WriteFileRequest) (*v1alpha1.WriteFileResponse, error) {
+	for {
+	}
+}
+
+func init() {
+	skiff.Register(new(SpinPlugin))
+}
+
+func main() {
+}
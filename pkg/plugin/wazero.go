@@ -7,12 +7,17 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/eddieowens/opts"
 	"github.com/skiff-sh/api/go/skiff/plugin/v1alpha1"
 	"github.com/skiff-sh/sdk-go/skiff/pluginapi"
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental"
 	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
 
 	"github.com/skiff-sh/skiff/pkg/bufferpool"
@@ -35,17 +40,94 @@ type Mount struct {
 
 const (
 	guestCWDPath = "/cwd"
+	// DefaultMemoryLimitPages 256 MiB.
+	DefaultMemoryLimitPages = 4096
+	// MaxMemoryLimitPages 4 GiB, the most a WASM memory can address.
+	MaxMemoryLimitPages   = 65536
+	DefaultRequestTimeout = 30 * time.Second
+
+	// MemoryPageSize the size of a WASM memory page.
+	MemoryPageSize = 64 << 10
 )
 
-func NewWazeroCompiler() (Compiler, error) {
+// ErrLimitExceeded returned when a plugin exceeds the memory or time it's allowed.
+var ErrLimitExceeded = errors.New("plugin limit exceeded")
+
+// WithCacheDir caches compiled plugins within dir so they're only compiled once.
+func WithCacheDir(dir string) opts.Opt[CompilerOpts] {
+	return func(c *CompilerOpts) {
+		c.CacheDir = dir
+	}
+}
+
+// WithMemoryLimitPages limits the memory of every plugin to pages of 64 KiB each.
+func WithMemoryLimitPages(pages uint32) opts.Opt[CompilerOpts] {
+	return func(c *CompilerOpts) {
+		c.MemoryLimitPages = pages
+	}
+}
+
+// WithRequestTimeout limits the time a plugin has to handle a request.
+func WithRequestTimeout(d time.Duration) opts.Opt[CompilerOpts] {
+	return func(c *CompilerOpts) {
+		c.RequestTimeout = d
+	}
+}
+
+// WithInterruptible aborts requests once their context is done even if there's no request timeout.
+func WithInterruptible(b bool) opts.Opt[CompilerOpts] {
+	return func(c *CompilerOpts) {
+		c.Interruptible = b
+	}
+}
+
+type CompilerOpts struct {
+	// If set, compiled plugins are cached within the directory.
+	CacheDir string
+	// Max memory pages of every plugin. If 0, plugins can use up to MaxMemoryLimitPages.
+	MemoryLimitPages uint32
+	// Max time a plugin has to start and to handle each request. If 0, requests aren't limited.
+	RequestTimeout time.Duration
+	// If true, requests are aborted once their context is done. Always the case if RequestTimeout is set. Off by
+	// default to avoid the overhead of watching the context on every call.
+	Interruptible bool
+}
+
+func (c CompilerOpts) DefaultOptions() CompilerOpts {
+	return CompilerOpts{
+		MemoryLimitPages: DefaultMemoryLimitPages,
+	}
+}
+
+func NewWazeroCompiler(op ...opts.Opt[CompilerOpts]) (Compiler, error) {
+	o := opts.DefaultApply(op...)
 	ctx := context.Background()
-	run := wazero.NewRuntime(ctx)
+
+	conf := wazero.NewRuntimeConfig()
+	if o.Interruptible || o.RequestTimeout > 0 {
+		// Allows requests to be interrupted.
+		conf = conf.WithCloseOnContextDone(true)
+	}
+	// The memory limit is enforced by the allocator of each plugin rather than the runtime so exceeding it can be
+	// detected.
+	if o.MemoryLimitPages > MaxMemoryLimitPages {
+		return nil, fmt.Errorf("memory limit can be at most %d pages", MaxMemoryLimitPages)
+	}
+	if o.CacheDir != "" {
+		cache, err := wazero.NewCompilationCacheWithDir(o.CacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create plugin cache: %w", err)
+		}
+		conf = conf.WithCompilationCache(cache)
+	}
+
+	run := wazero.NewRuntimeWithConfig(ctx, conf)
 	cl, err := wasi_snapshot_preview1.Instantiate(ctx, run)
 	if err != nil {
 		return nil, err
 	}
 
-	return &wazeroCompiler{Runtime: run, Closer: cl}, nil
+	return &wazeroCompiler{Runtime: run, Closer: cl, Opts: o}, nil
 }
 
 type Response struct {
@@ -76,6 +158,7 @@ type Plugin interface {
 type wazeroCompiler struct {
 	Runtime wazero.Runtime
 	Closer  api.Closer
+	Opts    CompilerOpts
 }
 
 func (w *wazeroCompiler) Compile(ctx context.Context, b []byte, opts CompileOpts) (Plugin, error) {
@@ -103,44 +186,70 @@ func (w *wazeroCompiler) Compile(ctx context.Context, b []byte, opts CompileOpts
 
 	modConfig = modConfig.WithFSConfig(mounts)
 
-	mod, err := w.Runtime.InstantiateWithConfig(ctx, b, modConfig)
+	compiled, err := w.Runtime.CompileModule(ctx, b)
 	if err != nil {
 		return nil, err
 	}
 
+	// Compilation isn't limited since it's done by the host.
+	limits := &limits{MemoryPages: w.Opts.MemoryLimitPages, Timeout: w.Opts.RequestTimeout}
+	startCtx, cancel := limits.context(ctx)
+	defer cancel()
+	if limits.MemoryPages > 0 {
+		startCtx = experimental.WithMemoryAllocator(startCtx, limits)
+	}
+	mod, err := w.Runtime.InstantiateModule(startCtx, compiled, modConfig)
+	if err == nil && limits.MemoryExceeded.Load() {
+		// The initial memory is always allocated.
+		_ = mod.Close(ctx)
+		err = errors.New("initial memory is too large")
+	}
+	if err != nil {
+		_ = compiled.Close(ctx)
+		return nil, limits.wrap(startCtx, err)
+	}
+
 	handleRequestFunc := mod.ExportedFunction(pluginapi.WASMFuncHandleRequestName)
 	if handleRequestFunc == nil {
+		_ = mod.Close(ctx)
+		_ = compiled.Close(ctx)
 		return nil, fmt.Errorf("func %s must be exported in your plugin", pluginapi.WASMFuncHandleRequestName)
 	}
 
 	def := handleRequestFunc.Definition()
 	if resultTypes := def.ResultTypes(); len(resultTypes) != 1 || resultTypes[0] != api.ValueTypeI64 {
+		_ = mod.Close(ctx)
+		_ = compiled.Close(ctx)
 		return nil, fmt.Errorf("func %s must return a single int64", pluginapi.WASMFuncHandleRequestName)
 	}
 
 	return &wazeroPlugin{
 		Module:            mod,
+		Compiled:          compiled,
 		Buffer:            buff,
 		HandleRequestFunc: handleRequestFunc,
 		MessageDelim:      '\r',
+		Limits:            limits,
 	}, nil
 }
 
 type wazeroPlugin struct {
-	Module api.Module
+	Module   api.Module
+	Compiled wazero.CompiledModule
 
 	HandleRequestFunc api.Function
 
 	Buffer       *execcmd.Buffers
 	MessageDelim byte
 	Closer       sync.Once
+	Limits       *limits
 }
 
 func (w *wazeroPlugin) Close() error {
 	var err error
 	w.Closer.Do(func() {
 		w.Buffer.Close()
-		err = w.Module.Close(context.Background())
+		err = errors.Join(w.Module.Close(context.Background()), w.Compiled.Close(context.Background()))
 	})
 	return err
 }
@@ -158,9 +267,11 @@ func (w *wazeroPlugin) SendRequest(ctx context.Context, req *v1alpha1.Request) (
 		return nil, err
 	}
 
-	res, err := w.HandleRequestFunc.Call(ctx)
+	callCtx, cancel := w.Limits.context(ctx)
+	defer cancel()
+	res, err := w.HandleRequestFunc.Call(callCtx)
 	if err != nil {
-		return nil, err
+		return nil, w.Limits.wrap(callCtx, err)
 	}
 
 	if code := pluginapi.ExitCode(res[0]); code != pluginapi.ExitCodeOK {
@@ -194,6 +305,76 @@ func (w *wazeroPlugin) SendRequest(ctx context.Context, req *v1alpha1.Request) (
 	return out, nil
 }
 
+var _ experimental.MemoryAllocator = (*limits)(nil)
+
+type limits struct {
+	MemoryPages uint32
+	Timeout     time.Duration
+	// Set once the plugin's memory failed to grow past MemoryPages.
+	MemoryExceeded atomic.Bool
+}
+
+// Allocate allocates the memory of a plugin which fails to grow past MemoryPages.
+func (l *limits) Allocate(_, _ uint64) experimental.LinearMemory {
+	return &limitedMemory{Limits: l}
+}
+
+func (l *limits) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if l.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, l.Timeout, ErrLimitExceeded)
+}
+
+// wrap returns an ErrLimitExceeded if err was caused by a limit, or the context's error if it was cancelled.
+func (l *limits) wrap(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), ErrLimitExceeded) {
+		return fmt.Errorf("%w: exceeded its %s time limit", ErrLimitExceeded, l.Timeout)
+	} else if ctx.Err() != nil {
+		return fmt.Errorf("plugin interrupted: %w", ctx.Err())
+	}
+
+	if l.MemoryExceeded.Load() {
+		return fmt.Errorf(
+			"%w: exceeded its %d MiB memory limit: %w",
+			ErrLimitExceeded,
+			uint64(l.MemoryPages)*MemoryPageSize>>20,
+			err,
+		)
+	}
+
+	return err
+}
+
+var _ experimental.LinearMemory = (*limitedMemory)(nil)
+
+// limitedMemory the memory of a plugin. Growing past the limit fails which the plugin can't recover from.
+type limitedMemory struct {
+	Limits *limits
+	Buf    []byte
+	// Whether the initial memory was allocated.
+	Allocated bool
+}
+
+func (l *limitedMemory) Reallocate(size uint64) []byte {
+	if size > uint64(l.Limits.MemoryPages)*MemoryPageSize {
+		l.Limits.MemoryExceeded.Store(true)
+		// The runtime panics if the initial memory fails to allocate so it's reported after instantiation instead.
+		if l.Allocated {
+			return nil
+		}
+	}
+	l.Allocated = true
+
+	// Memory never shrinks so the bytes past the current length are always zero.
+	l.Buf = slices.Grow(l.Buf, int(size)-len(l.Buf))[:size]
+	return l.Buf
+}
+
+func (l *limitedMemory) Free() {
+	l.Buf = nil
+}
+
 var _ fs.FS = (*noOpFS)(nil)
 
 type noOpFS struct {
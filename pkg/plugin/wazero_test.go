@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"io/fs"
@@ -9,6 +10,7 @@ import (
 	"testing/fstest"
 	"time"
 
+	"github.com/eddieowens/opts"
 	"github.com/skiff-sh/api/go/skiff/plugin/v1alpha1"
 	"github.com/stretchr/testify/suite"
 )
@@ -27,6 +29,10 @@ func (w *WazeroTestSuite) TestSendRequest() {
 		Expected   *v1alpha1.Response
 		Given      *v1alpha1.Request
 		RunCount   int
+		// Compiles with a cache dir.
+		Cached       bool
+		CompilerOpts []opts.Opt[CompilerOpts]
+		ExpectedErr  string
 	}
 
 	tests := map[string]test{
@@ -36,6 +42,23 @@ func (w *WazeroTestSuite) TestSendRequest() {
 			Expected:   &v1alpha1.Response{WriteFile: &v1alpha1.WriteFileResponse{Contents: []byte("hi")}},
 			Given:      &v1alpha1.Request{WriteFile: &v1alpha1.WriteFileRequest{}},
 		},
+		"cached": {
+			SourceName: "basic_plugin",
+			Cached:     true,
+			Expected:   &v1alpha1.Response{WriteFile: &v1alpha1.WriteFileResponse{Contents: []byte("hi")}},
+			Given:      &v1alpha1.Request{WriteFile: &v1alpha1.WriteFileRequest{}},
+		},
+		"time limit": {
+			SourceName:   "spin_plugin",
+			CompilerOpts: []opts.Opt[CompilerOpts]{WithRequestTimeout(100 * time.Millisecond)},
+			Given:        &v1alpha1.Request{WriteFile: &v1alpha1.WriteFileRequest{}},
+			ExpectedErr:  "plugin limit exceeded: exceeded its 100ms time limit",
+		},
+		"memory limit": {
+			SourceName:  "alloc_plugin",
+			Given:       &v1alpha1.Request{WriteFile: &v1alpha1.WriteFileRequest{}},
+			ExpectedErr: "plugin limit exceeded: exceeded its 256 MiB memory limit",
+		},
 		"file access": {
 			SourceName: "file_access_plugin",
 			Expected:   &v1alpha1.Response{WriteFile: &v1alpha1.WriteFileResponse{Contents: []byte("derp")}},
@@ -58,7 +81,13 @@ func (w *WazeroTestSuite) TestSendRequest() {
 				return
 			}
 
-			compiler, err := NewWazeroCompiler()
+			op := v.CompilerOpts
+			cacheDir := w.T().TempDir()
+			if v.Cached {
+				op = append(op, WithCacheDir(cacheDir))
+			}
+
+			compiler, err := NewWazeroCompiler(op...)
 			if !w.NoError(err) {
 				return
 			}
@@ -69,6 +98,11 @@ func (w *WazeroTestSuite) TestSendRequest() {
 				return
 			}
 
+			entries, err := ListCache(cacheDir)
+			if w.NoError(err) {
+				w.Equal(v.Cached, len(entries) > 0)
+			}
+
 			runCount := v.RunCount
 			if runCount == 0 {
 				runCount = 1
@@ -77,6 +111,11 @@ func (w *WazeroTestSuite) TestSendRequest() {
 			timer := time.Now()
 			for range runCount {
 				resp, err := plug.SendRequest(ctx, v.Given)
+				if v.ExpectedErr != "" {
+					w.ErrorIs(err, ErrLimitExceeded)
+					w.ErrorContains(err, v.ExpectedErr)
+					return
+				}
 				if !w.NoError(err) {
 					fmt.Println(string(resp.Logs()))
 					return
@@ -91,6 +130,52 @@ func (w *WazeroTestSuite) TestSendRequest() {
 	}
 }
 
+func (w *WazeroTestSuite) TestSendRequestCancelled() {
+	b, err := fs.ReadFile(testdata, "testdata/spin_plugin.wasm")
+	w.Require().NoError(err)
+
+	tests := map[string][]opts.Opt[CompilerOpts]{
+		"with timeout":    {WithRequestTimeout(time.Minute)},
+		"without timeout": {WithInterruptible(true)},
+	}
+
+	for desc, op := range tests {
+		w.Run(desc, func() {
+			compiler, err := NewWazeroCompiler(op...)
+			w.Require().NoError(err)
+
+			plug, err := compiler.Compile(w.T().Context(), b, CompileOpts{})
+			w.Require().NoError(err)
+			defer func() {
+				_ = plug.Close()
+			}()
+
+			ctx, cancel := context.WithCancel(w.T().Context())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			_, err = plug.SendRequest(ctx, &v1alpha1.Request{WriteFile: &v1alpha1.WriteFileRequest{}})
+			w.ErrorIs(err, context.Canceled)
+			w.NotErrorIs(err, ErrLimitExceeded)
+		})
+	}
+}
+
+func (w *WazeroTestSuite) TestSendRequestLogsOutOfMemory() {
+	b, err := fs.ReadFile(testdata, "testdata/oom_log_plugin.wasm")
+	w.Require().NoError(err)
+
+	compiler, err := NewWazeroCompiler()
+	w.Require().NoError(err)
+
+	plug, err := compiler.Compile(w.T().Context(), b, CompileOpts{})
+	w.Require().NoError(err)
+
+	// Only failing to grow memory past the limit is reported as exceeding it.
+	_, err = plug.SendRequest(w.T().Context(), &v1alpha1.Request{WriteFile: &v1alpha1.WriteFileRequest{}})
+	w.Error(err)
+	w.NotErrorIs(err, ErrLimitExceeded)
+}
+
 func TestWazeroTestSuite(t *testing.T) {
 	suite.Run(t, new(WazeroTestSuite))
 }
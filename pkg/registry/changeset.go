@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"github.com/skiff-sh/skiff/pkg/filesystem"
+)
+
+// Changeset writes files while remembering what they replaced so every write can be rolled back.
+type Changeset struct {
+	FS      filesystem.Filesystem
+	Changes []*Change
+}
+
+// Change a file written by a Changeset.
+type Change struct {
+	Path string
+	// The content before the write. Nil if the file didn't exist.
+	Previous []byte
+}
+
+func NewChangeset(fsys filesystem.Filesystem) *Changeset {
+	return &Changeset{FS: fsys}
+}
+
+// Write writes f, remembering its previous content.
+func (c *Changeset) Write(f *File) error {
+	prev, err := c.FS.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		prev = nil
+	} else if err != nil {
+		return err
+	} else if prev == nil {
+		prev = []byte{}
+	}
+
+	err = f.WriteTo(c.FS)
+	if err != nil {
+		return err
+	}
+
+	c.Changes = append(c.Changes, &Change{Path: f.Path, Previous: prev})
+	return nil
+}
+
+// Rollback undoes every write, latest first. Created files are removed but their directories are kept.
+func (c *Changeset) Rollback() error {
+	var errs []error
+	for _, v := range slices.Backward(c.Changes) {
+		var err error
+		if v.Previous == nil {
+			err = c.FS.Remove(v.Path)
+		} else {
+			err = c.FS.WriteFile(v.Path, v.Previous)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to roll back %s: %w", v.Path, err))
+		}
+	}
+	c.Changes = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/skiff-sh/skiff/pkg/filesystem"
+)
+
+type ChangesetTestSuite struct {
+	suite.Suite
+}
+
+func (c *ChangesetTestSuite) TestRollback() {
+	type test struct {
+		Given    map[string]string
+		Writes   []*File
+		Expected map[string]string
+	}
+
+	tests := map[string]test{
+		"restores edited files": {
+			Given: map[string]string{"main.go": "package main"},
+			Writes: []*File{
+				{Path: "main.go", Content: []byte("package other")},
+			},
+			Expected: map[string]string{"main.go": "package main"},
+		},
+		"removes created files": {
+			Given: map[string]string{"main.go": "package main"},
+			Writes: []*File{
+				{Path: "api/api.go", Content: []byte("package api")},
+			},
+			Expected: map[string]string{"main.go": "package main"},
+		},
+		"restores the original content of files written twice": {
+			Given: map[string]string{"main.go": "package main", "empty.go": ""},
+			Writes: []*File{
+				{Path: "main.go", Content: []byte("package a")},
+				{Path: "main.go", Content: []byte("package b")},
+				{Path: "empty.go", Content: []byte("package empty")},
+			},
+			Expected: map[string]string{"main.go": "package main", "empty.go": ""},
+		},
+	}
+
+	for desc, t := range tests {
+		c.Run(desc, func() {
+			root := c.T().TempDir()
+			for k, v := range t.Given {
+				c.Require().NoError(os.WriteFile(filepath.Join(root, k), []byte(v), 0o600))
+			}
+
+			changes := NewChangeset(filesystem.New(root))
+			for _, v := range t.Writes {
+				c.Require().NoError(changes.Write(v))
+			}
+
+			c.Require().NoError(changes.Rollback())
+			c.Empty(changes.Changes)
+
+			actual := map[string]string{}
+			err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				b, err := os.ReadFile(p)
+				if err != nil {
+					return err
+				}
+				rel, _ := filepath.Rel(root, p)
+				actual[rel] = string(b)
+				return nil
+			})
+			if c.NoError(err) {
+				c.Equal(t.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestChangesetTestSuite(t *testing.T) {
+	suite.Run(t, new(ChangesetTestSuite))
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
+	"strings"
 
 	pluginv1alpha1 "github.com/skiff-sh/api/go/skiff/plugin/v1alpha1"
 
@@ -20,6 +22,21 @@ import (
 	"github.com/skiff-sh/skiff/pkg/tmpl"
 )
 
+// PartialMarker marks a template as a partial when placed before its extension e.g. templates/helpers.partial.tmpl.
+const PartialMarker = ".partial"
+
+// IsPartial returns true if the file is a partial template i.e. its name ends with PartialMarker followed by a template
+// extension. Partials don't produce any output and their target is ignored but they're available to every other
+// template within the package via {{ template "name" . }} or {{ include "name" . }} where name is either the path of
+// the partial or the name of a {{ define }} block within it.
+func IsPartial(v *v1alpha1.File) bool {
+	ext := path.Ext(v.GetPath())
+	if _, ok := tmpl.EngineExtensions[ext]; !ok {
+		return false
+	}
+	return v.GetType() == v1alpha1.File_file && strings.HasSuffix(strings.TrimSuffix(v.GetPath(), ext), PartialMarker)
+}
+
 type PackageFile struct {
 	// The generator for the files contents.
 	Renderer ContentRenderer
@@ -56,7 +73,12 @@ func NewPackageFile(
 	var fi ContentRenderer
 	switch v.GetType() {
 	case v1alpha1.File_file:
-		fi, err = NewTemplateFileContentRenderer(tmplFact, src)
+		var contentFact tmpl.Factory
+		contentFact, err = tmplFact.WithEngine(tmpl.EngineForPath(v.GetPath()))
+		if err != nil {
+			return nil, err
+		}
+		fi, err = NewTemplateFileContentRenderer(contentFact, src)
 	case v1alpha1.File_plugin:
 		fi, err = NewPluginContentRenderer(ctx, src, compiler, sys)
 	default:
@@ -81,7 +103,62 @@ func NewPackageFile(
 	}, nil
 }
 
-func (p *PackageFile) GenerateFile(ctx context.Context, d schema.PackageDataSource) (*File, error) {
+const (
+	// ItemKey the data key holding the current element when a file fans out over a list via
+	// {{ each .list }} within its target. Nested under the reserved schema.ContextKey so it never replaces a field.
+	ItemKey = schema.ContextKey + ".item"
+
+	// IndexKey the data key holding the index of the current element when a file fans out over a list.
+	IndexKey = schema.ContextKey + ".index"
+)
+
+// GenerateFiles renders the file. A target that calls {{ each .list }} e.g.
+// {{ each .names }}controller/{{ .skiff.item }}.go produces one file per element in the list. The element and its index
+// are available to the target and contents as ItemKey and IndexKey respectively. Targets that render to an empty path
+// e.g. {{ if .with_tests }}main_test.go{{ end }} are skipped which allows files to be conditionally included.
+func (p *PackageFile) GenerateFiles(ctx context.Context, d schema.PackageDataSource) ([]*File, error) {
+	items, ok, err := p.Target.Each(d.RawData())
+	if err != nil {
+		return nil, fmt.Errorf("failed to render file path: %w", err)
+	}
+
+	if !ok {
+		fi, err := p.generateFile(ctx, d)
+		if err != nil || fi == nil {
+			return nil, err
+		}
+		return []*File{fi}, nil
+	}
+
+	out := make([]*File, 0, len(items))
+	for i, v := range items {
+		item, err := itemValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		itemData := schema.NewPackageSource()
+		for k, val := range d.Data() {
+			itemData.AddEntry(schema.NewEntry(k, val))
+		}
+		itemData.AddEntry(schema.NewEntry(ItemKey, item))
+		itemData.AddEntry(schema.NewEntry(IndexKey, schema.NewValidatedVal(float64(i), v1alpha1.Field_number, nil)))
+
+		fi, err := p.generateFile(ctx, itemData)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		if fi != nil {
+			out = append(out, fi)
+		}
+	}
+
+	return out, nil
+}
+
+// generateFile renders a single file. Returns nil if the target is empty.
+func (p *PackageFile) generateFile(ctx context.Context, d schema.PackageDataSource) (*File, error) {
 	out := &File{
 		SourcePath: p.File.GetPath(),
 	}
@@ -94,7 +171,10 @@ func (p *PackageFile) GenerateFile(ctx context.Context, d schema.PackageDataSour
 	if err != nil {
 		return nil, fmt.Errorf("failed to render file path: %w", err)
 	}
-	out.Path = buff.String()
+	out.Path = strings.TrimSpace(buff.String())
+	if out.Path == "" {
+		return nil, nil
+	}
 
 	c := &ContentRenderContext{
 		Ctx:     ctx,
@@ -111,6 +191,19 @@ func (p *PackageFile) GenerateFile(ctx context.Context, d schema.PackageDataSour
 	return out, nil
 }
 
+func itemValue(a any) (schema.Value, error) {
+	switch a.(type) {
+	case string:
+		return schema.NewValidatedVal(a, v1alpha1.Field_string, nil), nil
+	case float64:
+		return schema.NewValidatedVal(a, v1alpha1.Field_number, nil), nil
+	case bool:
+		return schema.NewValidatedVal(a, v1alpha1.Field_bool, nil), nil
+	default:
+		return nil, fmt.Errorf("cannot iterate over elements of type %T", a)
+	}
+}
+
 type ContentRenderContext struct {
 	Ctx context.Context
 
@@ -37,7 +37,35 @@ func NewPackageGenerator(
 		return nil, err
 	}
 
+	var partials []*tmpl.Partial
 	for _, v := range p.GetFiles() {
+		if !IsPartial(v) {
+			continue
+		}
+
+		src, _, err := resolveSource(p, v)
+		if err != nil {
+			return nil, fmt.Errorf("file %s: %w", v.GetPath(), err)
+		}
+		partials = append(partials, &tmpl.Partial{
+			Name:    v.GetPath(),
+			Content: src,
+			Engine:  tmpl.EngineForPath(v.GetPath()),
+		})
+	}
+
+	if len(partials) > 0 {
+		t, err = t.WithPartials(partials...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, v := range p.GetFiles() {
+		if IsPartial(v) {
+			continue
+		}
+
 		fi, err := NewPackageFile(ctx, compiler, sys, t, p, v)
 		if err != nil {
 			return nil, fmt.Errorf("file %s: %w", v.GetPath(), err)
@@ -56,12 +84,12 @@ func (p *PackageGenerator) Generate(ctx context.Context, d schema.PackageDataSou
 	}
 
 	for _, v := range p.Files {
-		fi, err := v.GenerateFile(ctx, d)
+		files, err := v.GenerateFiles(ctx, d)
 		if err != nil {
 			return nil, fmt.Errorf("file %s: %w", v.File.GetPath(), err)
 		}
 
-		out.Files = append(out.Files, fi)
+		out.Files = append(out.Files, files...)
 	}
 
 	return out, nil
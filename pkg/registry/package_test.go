@@ -0,0 +1,267 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
+	"github.com/skiff-sh/config/ptr"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/skiff-sh/skiff/pkg/schema"
+	"github.com/skiff-sh/skiff/pkg/tmpl"
+	"github.com/skiff-sh/skiff/pkg/valid"
+)
+
+type PackageTestSuite struct {
+	suite.Suite
+}
+
+func (p *PackageTestSuite) TestGenerate() {
+	type test struct {
+		Given                *v1alpha1.Package
+		GivenData            map[string]any
+		Expected             map[string]string
+		ExpectedGeneratorErr string
+		ExpectedErr          string
+	}
+
+	tests := map[string]test{
+		"partials are shared and not generated": {
+			Given: &v1alpha1.Package{
+				Name:        "pkg",
+				Description: "A package.",
+				Files: []*v1alpha1.File{
+					{
+						Path:   "templates/license.partial.tmpl",
+						Target: "unused",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`// Copyright {{ .owner }}`)},
+					},
+					{
+						Path:   "templates/helpers.partial.tmpl",
+						Target: "unused",
+						Source: &v1alpha1.File_Source{
+							Text: ptr.Ptr(`{{ define "header" }}{{ template "templates/license.partial.tmpl" . }}{{ end }}`),
+						},
+					},
+					{
+						Path:   "templates/a.tmpl",
+						Target: "a.go",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`{{ template "header" . }}` + "\npackage a")},
+					},
+					{
+						Path:   "templates/b.tmpl",
+						Target: "b.go",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`{{ include "header" . }}` + "\npackage b")},
+					},
+				},
+			},
+			GivenData: map[string]any{"owner": "skiff"},
+			Expected: map[string]string{
+				"a.go": "// Copyright skiff\npackage a",
+				"b.go": "// Copyright skiff\npackage b",
+			},
+		},
+		"engine is selected per file": {
+			Given: &v1alpha1.Package{
+				Name:        "pkg",
+				Description: "A package.",
+				Files: []*v1alpha1.File{
+					{
+						Path:   "templates/labels.partial.tmpl",
+						Target: "unused",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`app: {{ .name }}`)},
+					},
+					{
+						Path:   "chart/deployment.yaml.atmpl",
+						Target: "chart/templates/{{ .name }}.yaml",
+						Source: &v1alpha1.File_Source{
+							Text: ptr.Ptr(`labels:<% include "templates/labels.partial.tmpl" . | nindent 2 %>` +
+								"\nimage: {{ .Values.image }}"),
+						},
+					},
+					{
+						Path:   "templates/main.tmpl",
+						Target: "main.go",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`package {{ .name }}`)},
+					},
+				},
+			},
+			GivenData: map[string]any{"name": "api"},
+			Expected: map[string]string{
+				"chart/templates/api.yaml": "labels:\n  app: api\nimage: {{ .Values.image }}",
+				"main.go":                  "package api",
+			},
+		},
+		"files with an empty target are skipped": {
+			Given: &v1alpha1.Package{
+				Name:        "pkg",
+				Description: "A package.",
+				Files: []*v1alpha1.File{
+					{
+						Path:   "templates/main.tmpl",
+						Target: "main.go",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`package main`)},
+					},
+					{
+						Path:   "templates/main_test.tmpl",
+						Target: "{{ if .with_tests }}main_test.go{{ end }}",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`package main`)},
+					},
+					{
+						Path:   "templates/Dockerfile.tmpl",
+						Target: "{{ if .with_docker }}\n  Dockerfile\n{{ end }}",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`FROM {{ .image }}`)},
+					},
+					{
+						// Never rendered so the out of range index doesn't matter.
+						Path:   "templates/ci.tmpl",
+						Target: "{{ if .with_ci }}.github/workflows/ci.yaml{{ end }}",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`{{ index .image 10 }}`)},
+					},
+				},
+			},
+			GivenData: map[string]any{"with_tests": false, "with_docker": true, "with_ci": false, "image": "alpine"},
+			Expected: map[string]string{
+				"main.go":    "package main",
+				"Dockerfile": "FROM alpine",
+			},
+		},
+		"fan out over a list": {
+			Given: &v1alpha1.Package{
+				Name:        "pkg",
+				Description: "A package.",
+				Files: []*v1alpha1.File{
+					{
+						Path:   "templates/controller.tmpl",
+						Target: "{{ each .names }}controller/{{ .skiff.item | snakeCase }}.go",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`type {{ .skiff.item }} struct{} // {{ .skiff.index }} {{ .pkg }}`)},
+					},
+					{
+						Path:   "templates/test.tmpl",
+						Target: "{{ each .names }}{{ if eq .skiff.index 1.0 }}controller/{{ .skiff.item | snakeCase }}_test.go{{ end }}",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`package controller`)},
+					},
+					{
+						Path:   "templates/none.tmpl",
+						Target: "{{ each .none }}{{ .skiff.item }}.go",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`unused`)},
+					},
+				},
+			},
+			GivenData: map[string]any{"names": []string{"UserRoute", "OrderRoute"}, "none": []string{}, "pkg": "api"},
+			Expected: map[string]string{
+				"controller/user_route.go":       "type UserRoute struct{} // 0 api",
+				"controller/order_route.go":      "type OrderRoute struct{} // 1 api",
+				"controller/order_route_test.go": "package controller",
+			},
+		},
+		"fan out doesn't replace fields": {
+			Given: &v1alpha1.Package{
+				Name:        "pkg",
+				Description: "A package.",
+				Files: []*v1alpha1.File{
+					{
+						Path:   "templates/controller.tmpl",
+						Target: "{{ each .names }}{{ .skiff.item }}.go",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`{{ .item }} {{ .index }} {{ .skiff.item }}`)},
+					},
+				},
+			},
+			GivenData: map[string]any{"names": []string{"a"}, "item": "field", "index": "idx"},
+			Expected:  map[string]string{"a.go": "field idx a"},
+		},
+		"fan out over a non-list": {
+			Given: &v1alpha1.Package{
+				Name:        "pkg",
+				Description: "A package.",
+				Files: []*v1alpha1.File{
+					{
+						Path:   "templates/controller.tmpl",
+						Target: "{{ each .pkg }}{{ .skiff.item }}.go",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`unused`)},
+					},
+				},
+			},
+			GivenData:   map[string]any{"pkg": "api"},
+			ExpectedErr: "expected a list but got string",
+		},
+		"files named with an underscore are generated": {
+			Given: &v1alpha1.Package{
+				Name:        "pkg",
+				Description: "A package.",
+				Files: []*v1alpha1.File{
+					{
+						Path:   "templates/__init__.py",
+						Target: "{{ .name }}/__init__.py",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`# {{ .name }}`)},
+					},
+					{
+						Path:   "templates/_app.tsx",
+						Target: "pages/_app.tsx",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`export default App`)},
+					},
+				},
+			},
+			GivenData: map[string]any{"name": "api"},
+			Expected: map[string]string{
+				"api/__init__.py": "# api",
+				"pages/_app.tsx":  "export default App",
+			},
+		},
+		"partial cycle": {
+			Given: &v1alpha1.Package{
+				Name:        "pkg",
+				Description: "A package.",
+				Files: []*v1alpha1.File{
+					{
+						Path:   "a.partial.tmpl",
+						Target: "unused",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`{{ include "b.partial.tmpl" . }}`)},
+					},
+					{
+						Path:   "b.partial.tmpl",
+						Target: "unused",
+						Source: &v1alpha1.File_Source{Text: ptr.Ptr(`{{ template "a.partial.tmpl" . }}`)},
+					},
+				},
+			},
+			ExpectedGeneratorErr: "partial cycle detected: a.partial.tmpl -> b.partial.tmpl -> a.partial.tmpl",
+		},
+	}
+
+	for desc, v := range tests {
+		p.Run(desc, func() {
+			ctx := p.T().Context()
+			p.Require().NoError(valid.ValidateProto(v.Given))
+			gen, err := NewPackageGenerator(ctx, nil, nil, tmpl.NewGoFactory(), v.Given)
+			if v.ExpectedGeneratorErr != "" || !p.NoError(err) {
+				p.ErrorContains(err, v.ExpectedGeneratorErr)
+				return
+			}
+
+			pkg, err := gen.Generate(ctx, newTestDataSource(v.GivenData))
+			if v.ExpectedErr != "" || !p.NoError(err) {
+				p.ErrorContains(err, v.ExpectedErr)
+				return
+			}
+
+			actual := make(map[string]string, len(pkg.Files))
+			for _, fi := range pkg.Files {
+				actual[fi.Path] = string(fi.Content)
+			}
+			p.Equal(v.Expected, actual)
+		})
+	}
+}
+
+func TestPackageTestSuite(t *testing.T) {
+	suite.Run(t, new(PackageTestSuite))
+}
+
+func newTestDataSource(d map[string]any) schema.PackageDataSource {
+	out := schema.NewPackageSource()
+	for k, v := range d {
+		out.AddEntry(schema.NewEntry(k, schema.NewValidatedVal(v, v1alpha1.Field_string, nil)))
+	}
+	return out
+}
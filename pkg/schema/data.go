@@ -1,21 +1,48 @@
 package schema
 
-import pluginv1alpha1 "github.com/skiff-sh/api/go/skiff/plugin/v1alpha1"
+import (
+	"strings"
+
+	pluginv1alpha1 "github.com/skiff-sh/api/go/skiff/plugin/v1alpha1"
+	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
+)
+
+// IsShared returns true if the field is shared between packages. Shared fields are named by a well-known key
+// containing a "." e.g. project.module. They're only asked for once and every package that declares them receives the
+// same value.
+func IsShared(fieldName string) bool {
+	return strings.Contains(fieldName, ".")
+}
+
+// ContextKey is reserved for values detected about the user's project e.g. {{ .skiff.go.module }}. Fields can't be
+// named by it.
+const ContextKey = "skiff"
+
+// NewContextEntry creates an entry for a detected project value. name is keyed by the provider e.g. go.module.
+func NewContextEntry(name, v string) Entry {
+	return NewEntry(ContextKey+"."+name, NewValidatedVal(v, v1alpha1.Field_string, nil))
+}
 
 type DataSource interface {
 	AddPackageEntry(packageName string, v Entry)
+	// AddSharedEntry adds an entry to the global namespace which every package falls back to.
+	AddSharedEntry(v Entry)
+	// Package returns the data for a package including all shared entries. Package entries take precedence.
 	Package(name string) PackageDataSource
 	HasPackageEntry(packageName string, v Entry) bool
+	HasSharedEntry(fieldName string) bool
 }
 
 func NewDataSource() DataSource {
 	return &dataSource{
-		Map: make(map[string]PackageDataSource),
+		Map:    make(map[string]PackageDataSource),
+		Shared: NewPackageSource(),
 	}
 }
 
 type dataSource struct {
-	Map map[string]PackageDataSource
+	Map    map[string]PackageDataSource
+	Shared PackageDataSource
 }
 
 func (d *dataSource) HasPackageEntry(packageName string, e Entry) bool {
@@ -27,8 +54,23 @@ func (d *dataSource) HasPackageEntry(packageName string, e Entry) bool {
 	return v.Data()[e.FieldName()] != nil
 }
 
+func (d *dataSource) HasSharedEntry(fieldName string) bool {
+	return d.Shared.Data()[fieldName] != nil
+}
+
 func (d *dataSource) Package(name string) PackageDataSource {
-	return d.Map[name]
+	out := NewPackageSource()
+	for k, v := range d.Shared.Data() {
+		out.AddEntry(NewEntry(k, v))
+	}
+
+	if pkg := d.Map[name]; pkg != nil {
+		for k, v := range pkg.Data() {
+			out.AddEntry(NewEntry(k, v))
+		}
+	}
+
+	return out
 }
 
 func (d *dataSource) AddPackageEntry(packageName string, v Entry) {
@@ -40,6 +82,10 @@ func (d *dataSource) AddPackageEntry(packageName string, v Entry) {
 	}
 }
 
+func (d *dataSource) AddSharedEntry(v Entry) {
+	d.Shared.AddEntry(v)
+}
+
 type EntryAdder interface {
 	AddEntry(v Entry)
 }
@@ -78,16 +124,43 @@ func (d *packageDataSource) PluginData() map[string]*pluginv1alpha1.Value {
 	return out
 }
 
+// RawData returns the value of every entry. Shared fields are nested by their key e.g. project.module is available to
+// templates as {{ .project.module }}.
 func (d *packageDataSource) RawData() map[string]any {
 	out := map[string]any{}
 
 	for _, v := range d.Sources {
-		out[v.FieldName()] = v.Value().Any()
+		if !IsShared(v.FieldName()) {
+			out[v.FieldName()] = v.Value().Any()
+		}
+	}
+
+	// Nested after the package fields so they never overwrite one.
+	for _, v := range d.Sources {
+		if IsShared(v.FieldName()) {
+			setNested(out, strings.Split(v.FieldName(), "."), v.Value().Any())
+		}
 	}
 
 	return out
 }
 
+// setNested sets val within m at the path. Nothing is set if the path collides with an existing non-map value.
+func setNested(m map[string]any, keys []string, val any) {
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			if _, exists := m[k]; exists {
+				return
+			}
+			next = map[string]any{}
+			m[k] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = val
+}
+
 func (d *packageDataSource) Data() map[string]Value {
 	out := map[string]Value{}
 
@@ -105,3 +178,26 @@ func (d *packageDataSource) Package() string {
 func (d *packageDataSource) AddEntry(v Entry) {
 	d.Sources = append(d.Sources, v)
 }
+
+// NewEntry creates an Entry for a field with a fixed value.
+func NewEntry(fieldName string, v Value) Entry {
+	return &entry{
+		Name: fieldName,
+		Val:  v,
+	}
+}
+
+var _ Entry = (*entry)(nil)
+
+type entry struct {
+	Name string
+	Val  Value
+}
+
+func (e *entry) Value() Value {
+	return e.Val
+}
+
+func (e *entry) FieldName() string {
+	return e.Name
+}
@@ -0,0 +1,116 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/skiff-sh/config/ptr"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
+)
+
+type DataTestSuite struct {
+	suite.Suite
+}
+
+func (d *DataTestSuite) TestPackage() {
+	type test struct {
+		GivenShared  map[string]any
+		GivenPackage map[string]any
+		Expected     map[string]any
+	}
+
+	tests := map[string]test{
+		"no shared": {
+			GivenPackage: map[string]any{"name": "a"},
+			Expected:     map[string]any{"name": "a"},
+		},
+		"shared are nested": {
+			GivenShared:  map[string]any{"project.module": "mod", "project.name": "proj"},
+			GivenPackage: map[string]any{"name": "a"},
+			Expected: map[string]any{
+				"name":    "a",
+				"project": map[string]any{"module": "mod", "name": "proj"},
+			},
+		},
+		"only shared": {
+			GivenShared: map[string]any{"project.module": "mod"},
+			Expected:    map[string]any{"project": map[string]any{"module": "mod"}},
+		},
+		"package field takes precedence": {
+			GivenShared:  map[string]any{"project.module": "mod"},
+			GivenPackage: map[string]any{"project": "a"},
+			Expected:     map[string]any{"project": "a"},
+		},
+	}
+
+	for desc, t := range tests {
+		d.Run(desc, func() {
+			data := NewDataSource()
+			for k, v := range t.GivenShared {
+				data.AddSharedEntry(NewEntry(k, NewValidatedVal(v, v1alpha1.Field_string, nil)))
+				d.True(data.HasSharedEntry(k))
+			}
+			for k, v := range t.GivenPackage {
+				data.AddPackageEntry("pkg", NewEntry(k, NewValidatedVal(v, v1alpha1.Field_string, nil)))
+			}
+
+			d.Equal(t.Expected, data.Package("pkg").RawData())
+		})
+	}
+}
+
+func (d *DataTestSuite) TestContextKeyIsReserved() {
+	for _, name := range []string{ContextKey, ContextKey + ".go.module"} {
+		_, err := NewSchema(&v1alpha1.Schema{Fields: []*v1alpha1.Field{
+			{Name: name, Type: ptr.Ptr(v1alpha1.Field_string)},
+		}})
+		d.ErrorContains(err, "skiff is a reserved name")
+	}
+}
+
+func (d *DataTestSuite) TestSharedNames() {
+	type test struct {
+		Given       string
+		ExpectedErr string
+	}
+
+	tests := map[string]test{
+		"shared":           {Given: "project.module"},
+		"not shared":       {Given: "module"},
+		"too many keys":    {Given: "a.b.c", ExpectedErr: "shared fields must be named namespace.name"},
+		"empty key":        {Given: "project.", ExpectedErr: "shared fields must be named namespace.name"},
+		"invalid key char": {Given: "app.version-1", ExpectedErr: "shared fields must be named namespace.name"},
+	}
+
+	for desc, t := range tests {
+		d.Run(desc, func() {
+			_, err := NewSchema(&v1alpha1.Schema{Fields: []*v1alpha1.Field{
+				{Name: t.Given, Type: ptr.Ptr(v1alpha1.Field_string)},
+			}})
+			if t.ExpectedErr != "" {
+				d.ErrorContains(err, t.ExpectedErr)
+			} else {
+				d.NoError(err)
+			}
+		})
+	}
+}
+
+func (d *DataTestSuite) TestNewContextEntry() {
+	data := NewDataSource()
+	data.AddPackageEntry("pkg", NewEntry("name", NewValidatedVal("a", v1alpha1.Field_string, nil)))
+
+	pkg := data.Package("pkg")
+	pkg.AddEntry(NewContextEntry("go.module", "mod"))
+
+	d.Equal(map[string]any{
+		"name":  "a",
+		"skiff": map[string]any{"go": map[string]any{"module": "mod"}},
+	}, pkg.RawData())
+	d.Equal("mod", *pkg.PluginData()["skiff.go.module"].String)
+}
+
+func TestDataTestSuite(t *testing.T) {
+	suite.Run(t, new(DataTestSuite))
+}
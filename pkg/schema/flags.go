@@ -2,9 +2,6 @@ package schema
 
 import (
 	"context"
-	"fmt"
-	"slices"
-	"strings"
 
 	"github.com/urfave/cli/v3"
 
@@ -37,11 +34,13 @@ type FlagAccessor interface {
 	SetAliases(aliases []string)
 	SetRequired(b bool)
 	SetCategory(s string)
+	SetSources(s cli.ValueSourceChain)
 
 	Name() string
 	Aliases() []string
 	Required() bool
 	Category() string
+	Sources() cli.ValueSourceChain
 }
 
 type flagAccessor[T any, C any, VC cli.ValueCreator[T, C]] struct {
@@ -56,6 +55,14 @@ func (f *flagAccessor[T, C, VC]) Category() string {
 	return f.F.Category
 }
 
+func (f *flagAccessor[T, C, VC]) SetSources(s cli.ValueSourceChain) {
+	f.F.Sources = s
+}
+
+func (f *flagAccessor[T, C, VC]) Sources() cli.ValueSourceChain {
+	return f.F.Sources
+}
+
 func (f *flagAccessor[T, C, VC]) SetRequired(b bool) { f.F.Required = b }
 
 func (f *flagAccessor[T, C, VC]) Required() bool { return f.F.Required }
@@ -75,115 +82,55 @@ func newFlagAccessor[T any, C any, VC cli.ValueCreator[T, C]](f *cli.FlagBase[T,
 func FieldToCLIFlag(f *Field) *Flag {
 	switch f.Proto.GetType() {
 	case v1alpha1.Field_string:
-		enumVals := collection.Map(f.Enum, fields.Cast[string])
 		out := &cli.StringFlag{
-			Name:  f.Proto.GetName(),
-			Usage: f.Proto.GetDescription(),
-			Value: fields.Cast[string](f.Default),
-		}
-
-		if len(enumVals) > 0 {
-			out.Action = func(_ context.Context, _ *cli.Command, val string) error {
-				idx := slices.Index(enumVals, val)
-				if idx < 0 {
-					return fmt.Errorf(
-						"%s cannot be '%s': expected one of %s",
-						f.Proto.GetName(),
-						val,
-						strings.Join(enumVals, ", "),
-					)
-				}
-				return nil
-			}
+			Name:   f.Proto.GetName(),
+			Usage:  f.Proto.GetDescription(),
+			Value:  fields.Cast[string](f.Default),
+			Action: validateAction[string](f),
 		}
-
 		return &Flag{Field: f, Flag: out, Accessor: newFlagAccessor(out)}
 	case v1alpha1.Field_number:
-		enumVals := collection.Map(f.Enum, fields.Cast[float64])
 		out := &cli.Float64Flag{
-			Name:  f.Proto.GetName(),
-			Usage: f.Proto.GetDescription(),
-			Value: fields.Cast[float64](f.Default),
-		}
-
-		if len(enumVals) > 0 {
-			out.Action = func(_ context.Context, _ *cli.Command, val float64) error {
-				idx := slices.Index(enumVals, val)
-				if idx < 0 {
-					return fmt.Errorf(
-						"%s cannot be '%v': expected one of %s",
-						f.Proto.GetName(),
-						val,
-						strings.Join(collection.Map(enumVals, fields.FormatFloat), ", "),
-					)
-				}
-				return nil
-			}
+			Name:   f.Proto.GetName(),
+			Usage:  f.Proto.GetDescription(),
+			Value:  fields.Cast[float64](f.Default),
+			Action: validateAction[float64](f),
 		}
-
 		return &Flag{Field: f, Flag: out, Accessor: newFlagAccessor(out)}
 	case v1alpha1.Field_bool:
 		out := &cli.BoolFlag{
-			Name:  f.Proto.GetName(),
-			Usage: f.Proto.GetDescription(),
-			Value: fields.Cast[bool](f.Default),
+			Name:   f.Proto.GetName(),
+			Usage:  f.Proto.GetDescription(),
+			Value:  fields.Cast[bool](f.Default),
+			Action: validateAction[bool](f),
 		}
 		return &Flag{Field: f, Flag: out, Accessor: newFlagAccessor(out)}
 	case v1alpha1.Field_array:
 		//nolint:exhaustive // can only be a subset.
 		switch f.Proto.GetItems().GetType() {
 		case v1alpha1.Field_string:
-			enumVals := collection.Map(f.Enum, fields.Cast[string])
 			out := &cli.StringSliceFlag{
-				Name:  f.Proto.GetName(),
-				Usage: f.Proto.GetDescription(),
-				Value: collection.Map(fields.Cast[[]any](f.Default), fields.Cast[string]),
-			}
-
-			if len(enumVals) > 0 {
-				out.Action = func(_ context.Context, _ *cli.Command, val []string) error {
-					for _, v := range val {
-						idx := slices.Index(enumVals, v)
-						if idx < 0 {
-							return fmt.Errorf(
-								"%s cannot be %s: expected one of %s",
-								f.Proto.GetName(),
-								v,
-								strings.Join(enumVals, ", "),
-							)
-						}
-					}
-					return nil
-				}
+				Name:   f.Proto.GetName(),
+				Usage:  f.Proto.GetDescription(),
+				Value:  collection.Map(fields.Cast[[]any](f.Default), fields.Cast[string]),
+				Action: validateAction[[]string](f),
 			}
 			return &Flag{Field: f, Flag: out, Accessor: newFlagAccessor(out)}
 		case v1alpha1.Field_number:
-			enumVals := collection.Map(f.Enum, fields.Cast[float64])
 			out := &cli.Float64SliceFlag{
-				Name:  f.Proto.GetName(),
-				Usage: f.Proto.GetDescription(),
-				Value: collection.Map(fields.Cast[[]any](f.Default), fields.Cast[float64]),
-			}
-
-			if len(enumVals) > 0 {
-				out.Action = func(_ context.Context, _ *cli.Command, val []float64) error {
-					for _, v := range val {
-						idx := slices.Index(enumVals, v)
-						if idx < 0 {
-							return fmt.Errorf(
-								"%s cannot be %v: expected one of %s",
-								f.Proto.GetName(),
-								v,
-								strings.Join(collection.Map(enumVals, fields.FormatFloat), ", "),
-							)
-						}
-					}
-					return nil
-				}
+				Name:   f.Proto.GetName(),
+				Usage:  f.Proto.GetDescription(),
+				Value:  collection.Map(fields.Cast[[]any](f.Default), fields.Cast[float64]),
+				Action: validateAction[[]float64](f),
 			}
-
 			return &Flag{Field: f, Flag: out, Accessor: newFlagAccessor(out)}
 		}
 	}
 	return nil
 }
+
+func validateAction[T any](f *Field) func(_ context.Context, _ *cli.Command, val T) error {
+	return func(_ context.Context, _ *cli.Command, val T) error {
+		return f.Validate(val)
+	}
+}
@@ -1,8 +1,6 @@
 package schema
 
 import (
-	"errors"
-	"fmt"
 	"slices"
 	"strconv"
 	"strings"
@@ -51,31 +49,28 @@ func NewFormField(f *Field) *FormField {
 			}
 		}
 
+		if out.Accessor != nil && len(f.Enum) > longEnumLen {
+			out.Accessor.SetDescription("Type / to filter.")
+		}
+
 		return out
 	}
 
 	switch f.Proto.GetType() {
 	case v1alpha1.Field_string, v1alpha1.Field_number, v1alpha1.Field_bool:
-		o, getter := newPrimitive(f.Proto.GetType())
+		o, getter := newPrimitive(f)
 		if o == nil {
 			return out
 		}
 		out.FormFields = append(out.FormFields, o)
 		out.Accessor = getter
 	case v1alpha1.Field_array:
-		var val string
-		valParser := stringParser
-		if f.Proto.GetItems().GetType() == v1alpha1.Field_number {
-			valParser = floatParser
-		}
+		val := strings.Join(collection.Map(fields.Cast[[]any](f.Default), formatVal), "\n")
 		txt := huh.NewText().
 			Lines(lineCount).
 			ShowLineNumbers(true).
 			Value(&val).
-			Validate(func(s string) error {
-				_, err := parseStringList(s, valParser)
-				return err
-			})
+			Validate(validateParse(f))
 
 		out.Accessor = newTextHuhAccessor(txt, f.Proto.GetItems().GetType())
 		out.FormFields = append(out.FormFields, txt)
@@ -260,7 +255,13 @@ func (h *huhValueAccessor) Value() Value {
 	return h.ValueSource.Value()
 }
 
-const lineCount = 5
+const (
+	lineCount = 5
+	// Selects with more options than this scroll instead of showing every option.
+	longEnumLen = 8
+	// The lines of a select's title and description which count towards its height.
+	selectHeaderLines = 2
+)
 
 func FlattenHuhFields(fields []*FormField) []huh.Field {
 	out := make([]huh.Field, 0, len(fields))
@@ -270,58 +271,29 @@ func FlattenHuhFields(fields []*FormField) []huh.Field {
 	return out
 }
 
-type valueParser func(s string) (any, error)
-
-var (
-	floatParser valueParser = func(s string) (any, error) {
-		fl, err := strconv.ParseFloat(s, 64)
-		if err != nil {
-			return nil, errors.New("not a number")
-		}
-		return fl, nil
-	}
-
-	stringParser valueParser = func(s string) (any, error) {
-		return s, nil
+// validateParse validates the raw input of a form field.
+func validateParse(f *Field) func(s string) error {
+	return func(s string) error {
+		_, err := f.Parse(s)
+		return err
 	}
-)
-
-func parseStringList(s string, parser valueParser) ([]any, error) {
-	lines := strings.Split(s, "\n")
-	out := make([]any, 0, len(lines))
-	for i, v := range lines {
-		li, err := parser(v)
-		if err != nil {
-			return nil, fmt.Errorf("entry #%d invalid: %w", i+1, err)
-		}
-		out = append(out, li)
-	}
-
-	return out, nil
 }
 
-func newPrimitive(typ v1alpha1.Field_Type) (huh.Field, HuhValueAccessor) {
+func newPrimitive(f *Field) (huh.Field, HuhValueAccessor) {
+	typ := f.Proto.GetType()
 	//nolint:exhaustive // can only be a subset.
 	switch typ {
-	case v1alpha1.Field_string:
-		var val string
-		out := huh.NewInput().
-			Value(&val)
-		return out, newInputHuhAccessor(out, typ)
-	case v1alpha1.Field_number:
+	case v1alpha1.Field_string, v1alpha1.Field_number:
 		var val string
+		if f.Default != nil {
+			val = formatVal(f.Default)
+		}
 		out := huh.NewInput().
 			Value(&val).
-			Validate(func(s string) error {
-				_, err := strconv.ParseFloat(s, 64)
-				if err != nil {
-					return errors.New("must be a number")
-				}
-				return nil
-			})
+			Validate(validateParse(f))
 		return out, newInputHuhAccessor(out, typ)
 	case v1alpha1.Field_bool:
-		var val bool
+		val := fields.Cast[bool](f.Default)
 		out := huh.NewConfirm().Value(&val)
 		return out, newConfirmHuhAccessor(out)
 	}
@@ -340,6 +312,9 @@ func newSelect[T string | float64](f *Field) (huh.Field, HuhValueAccessor) {
 				val := fields.Cast[T](e)
 				return huh.NewOption(anyStringer(e), val).Selected(e == f.Default)
 			})...)
+		if len(f.Enum) > longEnumLen {
+			out.Height(longEnumLen + selectHeaderLines)
+		}
 		return out, newSelectHuhAccessor(out, f)
 	case v1alpha1.Field_array:
 		var val []T
@@ -350,6 +325,9 @@ func newSelect[T string | float64](f *Field) (huh.Field, HuhValueAccessor) {
 				val := fields.Cast[T](e)
 				return huh.NewOption(anyStringer(e), val).Selected(slices.Contains(def, e))
 			})...)
+		if len(f.Enum) > longEnumLen {
+			out.Height(longEnumLen + selectHeaderLines)
+		}
 		return out, newMultiSelectHuhAccessor(out, f)
 	}
 	return nil, nil
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/x/exp/teatest"
 	"github.com/skiff-sh/config/ptr"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
 
@@ -52,6 +53,45 @@ func (f *FormTestSuite) TestFormField() {
 			Input:         testutil.Inputs("123", tea.KeyEnter),
 			ExpectedValue: float64(123),
 		},
+		"string default is pre-filled": {
+			Given: &v1alpha1.Field{
+				Name:    "field",
+				Type:    ptr.Ptr(v1alpha1.Field_string),
+				Default: structpb.NewStringValue("derp"),
+			},
+			Input:         testutil.Inputs(tea.KeyEnter),
+			ExpectedValue: "derp",
+		},
+		"number default is pre-filled": {
+			Given: &v1alpha1.Field{
+				Name:    "field",
+				Type:    ptr.Ptr(v1alpha1.Field_number),
+				Default: structpb.NewNumberValue(1.5),
+			},
+			Input:         testutil.Inputs("2", tea.KeyEnter),
+			ExpectedValue: 1.52,
+		},
+		"long number default is pre-filled": {
+			Given: &v1alpha1.Field{
+				Name:    "field",
+				Type:    ptr.Ptr(v1alpha1.Field_number),
+				Default: structpb.NewNumberValue(12345),
+			},
+			Input:         testutil.Inputs(tea.KeyEnter),
+			ExpectedValue: float64(12345),
+		},
+		"list of numbers default is pre-filled": {
+			Given: &v1alpha1.Field{
+				Name: "field",
+				Type: ptr.Ptr(v1alpha1.Field_array),
+				Items: &v1alpha1.Field_SubField{
+					Type: ptr.Ptr(v1alpha1.Field_number),
+				},
+				Default: structpb.NewListValue(fields.NewListValue(8080, 12345)),
+			},
+			Input:         testutil.Inputs(tea.KeyEnter),
+			ExpectedValue: []float64{8080, 12345},
+		},
 		"bool": {
 			Given: &v1alpha1.Field{
 				Name: "field",
@@ -69,6 +109,15 @@ func (f *FormTestSuite) TestFormField() {
 			Input:         testutil.Inputs(tea.KeyDown, tea.KeyEnter),
 			ExpectedValue: "b",
 		},
+		"long string select is filterable": {
+			Given: &v1alpha1.Field{
+				Name: "field",
+				Type: ptr.Ptr(v1alpha1.Field_string),
+				Enum: fields.NewListValue("a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"),
+			},
+			Input:         testutil.Inputs("/", "k", tea.KeyEnter, tea.KeyEnter),
+			ExpectedValue: "k",
+		},
 		"string select default": {
 			Given: &v1alpha1.Field{
 				Name:    "field",
@@ -161,7 +210,7 @@ func (f *FormTestSuite) TestFormField() {
 				},
 			},
 			Input:           testutil.Inputs("abc", "alt+enter", "def", tea.KeyEnter),
-			ExpectedFormErr: "not a number",
+			ExpectedFormErr: "field cannot be 'abc': must be a number",
 		},
 		"list of number enums": {
 			Given: &v1alpha1.Field{
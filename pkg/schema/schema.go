@@ -3,6 +3,8 @@ package schema
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/skiff-sh/skiff/pkg/fields"
 )
 
+// sharedNameRegex the form of shared field names i.e. namespace.name.
+var sharedNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*\.[A-Za-z][A-Za-z0-9_]*$`)
+
 type Schema struct {
 	Proto  *v1alpha1.Schema
 	Fields []*Field
@@ -23,6 +28,15 @@ func NewSchema(sch *v1alpha1.Schema) (*Schema, error) {
 	}
 
 	for _, field := range sch.GetFields() {
+		if field.GetName() == ContextKey || strings.HasPrefix(field.GetName(), ContextKey+".") {
+			return nil, fmt.Errorf("field '%s': %s is a reserved name", field.GetName(), ContextKey)
+		}
+
+		// Dotted names change the meaning of a field so any that aren't clearly meant to be shared are rejected.
+		if IsShared(field.GetName()) && !sharedNameRegex.MatchString(field.GetName()) {
+			return nil, fmt.Errorf("field '%s': shared fields must be named namespace.name e.g. project.module", field.GetName())
+		}
+
 		f, err := NewField(field)
 		if err != nil {
 			return nil, fmt.Errorf("field '%s': %w", field.GetName(), err)
@@ -79,6 +93,16 @@ func NewField(p *v1alpha1.Field) (*Field, error) {
 	return out, nil
 }
 
+// WithDefault returns a copy of the field with v as the default. v is the Go representation of the field's type.
+func (f *Field) WithDefault(v any) *Field {
+	out := *f
+	if items, ok := toAnySlice(v); ok {
+		v = items
+	}
+	out.Default = v
+	return &out
+}
+
 func getDefault(p *v1alpha1.Field) (any, error) {
 	if p.GetDefault() == nil {
 		return nil, nil
@@ -0,0 +1,181 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
+
+	"github.com/skiff-sh/skiff/pkg/collection"
+	"github.com/skiff-sh/skiff/pkg/fields"
+)
+
+// Validate returns an error if v is not a valid value for the field. v is the Go representation of the field's type
+// i.e. string, float64, bool, or a slice of the items type. Every source of data (flags, forms, etc.) validates through
+// here so the messages are the same regardless of how the value was provided.
+func (f *Field) Validate(v any) error {
+	if f.Proto.GetType() != v1alpha1.Field_array {
+		return f.validatePrimitive(v, f.Proto.GetType())
+	}
+
+	items, ok := toAnySlice(v)
+	if !ok {
+		return fmt.Errorf("%s must be a list", f.Proto.GetName())
+	}
+
+	for _, item := range items {
+		err := f.validatePrimitive(item, f.Proto.GetItems().GetType())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Parse converts s into the Go representation of the field's type and validates it. Arrays are parsed one entry per
+// line.
+func (f *Field) Parse(s string) (any, error) {
+	if f.Proto.GetType() != v1alpha1.Field_array {
+		v, err := f.parsePrimitive(s, f.Proto.GetType())
+		if err != nil {
+			return nil, err
+		}
+		return v, f.Validate(v)
+	}
+
+	lines := strings.Split(s, "\n")
+	var out any
+	if f.Proto.GetItems().GetType() == v1alpha1.Field_number {
+		nums := make([]float64, 0, len(lines))
+		for _, v := range lines {
+			n, err := f.parsePrimitive(v, v1alpha1.Field_number)
+			if err != nil {
+				return nil, err
+			}
+			nums = append(nums, fields.Cast[float64](n))
+		}
+		out = nums
+	} else {
+		out = lines
+	}
+
+	return out, f.Validate(out)
+}
+
+func (f *Field) parsePrimitive(s string, typ v1alpha1.Field_Type) (any, error) {
+	//nolint:exhaustive // can only be a primitive.
+	switch typ {
+	case v1alpha1.Field_number:
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, f.typeError(s, typ)
+		}
+		return v, nil
+	case v1alpha1.Field_bool:
+		v, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, f.typeError(s, typ)
+		}
+		return v, nil
+	}
+	return s, nil
+}
+
+func (f *Field) validatePrimitive(v any, typ v1alpha1.Field_Type) error {
+	var ok bool
+	//nolint:exhaustive // can only be a primitive.
+	switch typ {
+	case v1alpha1.Field_string:
+		_, ok = v.(string)
+	case v1alpha1.Field_number:
+		_, ok = v.(float64)
+	case v1alpha1.Field_bool:
+		_, ok = v.(bool)
+	}
+	if !ok {
+		return f.typeError(v, typ)
+	}
+
+	if len(f.Enum) > 0 && !slices.Contains(f.Enum, v) {
+		return fmt.Errorf(
+			"%s cannot be '%s': expected one of %s",
+			f.Proto.GetName(),
+			formatVal(v),
+			strings.Join(collection.Map(f.Enum, formatVal), ", "),
+		)
+	}
+
+	return nil
+}
+
+func (f *Field) typeError(v any, typ v1alpha1.Field_Type) error {
+	return fmt.Errorf("%s cannot be '%s': must be a %s", f.Proto.GetName(), formatVal(v), typ.String())
+}
+
+// formatVal formats v the way a user would type it. Numbers are never rounded so they survive a round trip through
+// Parse.
+func formatVal(v any) string {
+	if fl, ok := v.(float64); ok {
+		return strconv.FormatFloat(fl, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func toAnySlice(v any) ([]any, bool) {
+	switch typ := v.(type) {
+	case []any:
+		return typ, true
+	case []string:
+		return collection.Map(typ, func(e string) any { return e }), true
+	case []float64:
+		return collection.Map(typ, func(e float64) any { return e }), true
+	}
+	return nil, false
+}
+
+// Coerce converts a value decoded from JSON or YAML into the Go representation of the field's type and validates it.
+func (f *Field) Coerce(v any) (any, error) {
+	if f.Proto.GetType() != v1alpha1.Field_array {
+		v = coercePrimitive(v, f.Proto.GetType())
+		return v, f.Validate(v)
+	}
+
+	items, ok := toAnySlice(v)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", f.Proto.GetName())
+	}
+
+	itemsTyp := f.Proto.GetItems().GetType()
+	coerced := collection.Map(items, func(e any) any {
+		return coercePrimitive(e, itemsTyp)
+	})
+	err := f.Validate(coerced)
+	if err != nil {
+		return nil, err
+	}
+
+	if itemsTyp == v1alpha1.Field_number {
+		return collection.Map(coerced, fields.Cast[float64]), nil
+	}
+	return collection.Map(coerced, fields.Cast[string]), nil
+}
+
+// coercePrimitive converts the numeric types produced by decoders into float64.
+func coercePrimitive(v any, typ v1alpha1.Field_Type) any {
+	if typ != v1alpha1.Field_number {
+		return v
+	}
+
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	}
+	return v
+}
@@ -0,0 +1,187 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/skiff-sh/config/ptr"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
+
+	"github.com/skiff-sh/skiff/pkg/fields"
+)
+
+type ValidateTestSuite struct {
+	suite.Suite
+}
+
+func (v *ValidateTestSuite) TestValidate() {
+	type test struct {
+		Given       *v1alpha1.Field
+		GivenVal    any
+		ExpectedErr string
+	}
+
+	tests := map[string]test{
+		"string": {
+			Given:    &v1alpha1.Field{Name: "field", Type: ptr.Ptr(v1alpha1.Field_string)},
+			GivenVal: "a",
+		},
+		"wrong type": {
+			Given:       &v1alpha1.Field{Name: "field", Type: ptr.Ptr(v1alpha1.Field_number)},
+			GivenVal:    "a",
+			ExpectedErr: "field cannot be 'a': must be a number",
+		},
+		"number enum": {
+			Given: &v1alpha1.Field{
+				Name: "field",
+				Type: ptr.Ptr(v1alpha1.Field_number),
+				Enum: fields.NewListValue(1, 2),
+			},
+			GivenVal: 2.0,
+		},
+		"number enum invalid": {
+			Given: &v1alpha1.Field{
+				Name: "field",
+				Type: ptr.Ptr(v1alpha1.Field_number),
+				Enum: fields.NewListValue(1, 2),
+			},
+			GivenVal:    1.5,
+			ExpectedErr: "field cannot be '1.5': expected one of 1, 2",
+		},
+		"long number enum invalid": {
+			Given: &v1alpha1.Field{
+				Name: "port",
+				Type: ptr.Ptr(v1alpha1.Field_number),
+				Enum: fields.NewListValue(8080, 8443),
+			},
+			GivenVal:    12345.0,
+			ExpectedErr: "port cannot be '12345': expected one of 8080, 8443",
+		},
+		"string list enum": {
+			Given: &v1alpha1.Field{
+				Name: "field",
+				Type: ptr.Ptr(v1alpha1.Field_array),
+				Items: &v1alpha1.Field_SubField{
+					Type: ptr.Ptr(v1alpha1.Field_string),
+					Enum: fields.NewListValue("a", "b"),
+				},
+			},
+			GivenVal: []string{"b", "a"},
+		},
+		"string list enum invalid": {
+			Given: &v1alpha1.Field{
+				Name: "field",
+				Type: ptr.Ptr(v1alpha1.Field_array),
+				Items: &v1alpha1.Field_SubField{
+					Type: ptr.Ptr(v1alpha1.Field_string),
+					Enum: fields.NewListValue("a", "b"),
+				},
+			},
+			GivenVal:    []any{"a", "c"},
+			ExpectedErr: "field cannot be 'c': expected one of a, b",
+		},
+		"list not a list": {
+			Given: &v1alpha1.Field{
+				Name:  "field",
+				Type:  ptr.Ptr(v1alpha1.Field_array),
+				Items: &v1alpha1.Field_SubField{Type: ptr.Ptr(v1alpha1.Field_number)},
+			},
+			GivenVal:    1.0,
+			ExpectedErr: "field must be a list",
+		},
+	}
+
+	for desc, t := range tests {
+		v.Run(desc, func() {
+			sch, err := NewSchema(&v1alpha1.Schema{Fields: []*v1alpha1.Field{t.Given}})
+			if !v.NoError(err) {
+				return
+			}
+
+			err = sch.Fields[0].Validate(t.GivenVal)
+			if t.ExpectedErr != "" || !v.NoError(err) {
+				v.ErrorContains(err, t.ExpectedErr)
+			}
+		})
+	}
+}
+
+func (v *ValidateTestSuite) TestParse() {
+	type test struct {
+		Given       *v1alpha1.Field
+		GivenVal    string
+		Expected    any
+		ExpectedErr string
+	}
+
+	tests := map[string]test{
+		"string": {
+			Given:    &v1alpha1.Field{Name: "field", Type: ptr.Ptr(v1alpha1.Field_string)},
+			GivenVal: "a",
+			Expected: "a",
+		},
+		"number": {
+			Given:    &v1alpha1.Field{Name: "field", Type: ptr.Ptr(v1alpha1.Field_number)},
+			GivenVal: " 1.5 ",
+			Expected: 1.5,
+		},
+		"invalid number": {
+			Given:       &v1alpha1.Field{Name: "field", Type: ptr.Ptr(v1alpha1.Field_number)},
+			GivenVal:    "abc",
+			ExpectedErr: "field cannot be 'abc': must be a number",
+		},
+		"bool": {
+			Given:    &v1alpha1.Field{Name: "field", Type: ptr.Ptr(v1alpha1.Field_bool)},
+			GivenVal: "true",
+			Expected: true,
+		},
+		"string enum invalid": {
+			Given: &v1alpha1.Field{
+				Name: "field",
+				Type: ptr.Ptr(v1alpha1.Field_string),
+				Enum: fields.NewListValue("a"),
+			},
+			GivenVal:    "b",
+			ExpectedErr: "field cannot be 'b': expected one of a",
+		},
+		"number list": {
+			Given: &v1alpha1.Field{
+				Name:  "field",
+				Type:  ptr.Ptr(v1alpha1.Field_array),
+				Items: &v1alpha1.Field_SubField{Type: ptr.Ptr(v1alpha1.Field_number)},
+			},
+			GivenVal: "1\n2",
+			Expected: []float64{1, 2},
+		},
+		"number list invalid": {
+			Given: &v1alpha1.Field{
+				Name:  "field",
+				Type:  ptr.Ptr(v1alpha1.Field_array),
+				Items: &v1alpha1.Field_SubField{Type: ptr.Ptr(v1alpha1.Field_number)},
+			},
+			GivenVal:    "1\nabc",
+			ExpectedErr: "field cannot be 'abc': must be a number",
+		},
+	}
+
+	for desc, t := range tests {
+		v.Run(desc, func() {
+			sch, err := NewSchema(&v1alpha1.Schema{Fields: []*v1alpha1.Field{t.Given}})
+			if !v.NoError(err) {
+				return
+			}
+
+			actual, err := sch.Fields[0].Parse(t.GivenVal)
+			if t.ExpectedErr != "" || !v.NoError(err) {
+				v.ErrorContains(err, t.ExpectedErr)
+				return
+			}
+			v.Equal(t.Expected, actual)
+		})
+	}
+}
+
+func TestValidateTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidateTestSuite))
+}
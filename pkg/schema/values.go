@@ -0,0 +1,135 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Values field values keyed by package name and then field name e.g.
+//
+//	create-http-route:
+//	  name: users
+//	  method: GET
+type Values map[string]map[string]any
+
+// ParseValues parses YAML or JSON values.
+func ParseValues(b []byte) (Values, error) {
+	raw := map[string]any{}
+	err := yaml.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(Values, len(raw))
+	for pkg, v := range raw {
+		if v == nil {
+			continue
+		}
+
+		fieldVals, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected a map of field names to values", pkg)
+		}
+		out[pkg] = fieldVals
+	}
+
+	return out, nil
+}
+
+// Resolve converts every value into the Go representation of its field's type. fields maps package names to their
+// fields. Unknown packages, unknown fields, and invalid values are all reported with the path to the value e.g.
+// create-http-route.method.
+func (v Values) Resolve(fields map[string][]*Field) error {
+	var errs []error
+	for _, pkg := range slices.Sorted(maps.Keys(v)) {
+		pkgFields, ok := fields[pkg]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown package", pkg))
+			continue
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(v[pkg])) {
+			idx := slices.IndexFunc(pkgFields, func(f *Field) bool {
+				return f.Proto.GetName() == name
+			})
+			if idx < 0 {
+				errs = append(errs, fmt.Errorf("%s.%s: unknown field", pkg, name))
+				continue
+			}
+
+			val, err := pkgFields[idx].Coerce(v[pkg][name])
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s: %w", pkg, name, err))
+				continue
+			}
+			v[pkg][name] = val
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Merge copies all values from o into v. Values in o take precedence.
+func (v Values) Merge(o Values) {
+	for pkg, vals := range o {
+		if v[pkg] == nil {
+			v[pkg] = make(map[string]any, len(vals))
+		}
+		maps.Copy(v[pkg], vals)
+	}
+}
+
+// CheckShared returns an error if a shared field (see IsShared) is given different values under different packages
+// since only one of them can be used.
+func (v Values) CheckShared() error {
+	type source struct {
+		Package string
+		Value   any
+	}
+
+	var errs []error
+	first := map[string]source{}
+	for _, pkg := range slices.Sorted(maps.Keys(v)) {
+		for _, name := range slices.Sorted(maps.Keys(v[pkg])) {
+			if !IsShared(name) {
+				continue
+			}
+
+			src, ok := first[name]
+			if !ok {
+				first[name] = source{Package: pkg, Value: v[pkg][name]}
+				continue
+			}
+
+			if !reflect.DeepEqual(src.Value, v[pkg][name]) {
+				errs = append(errs, fmt.Errorf("%s.%s: shared field has a different value under %s", pkg, name, src.Package))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Entry returns the value of the field within the package. Returns false if the value was not provided. Shared fields
+// (see IsShared) can be provided under any package.
+func (v Values) Entry(pkg string, f *Field) (Entry, bool) {
+	val, ok := v[pkg][f.Proto.GetName()]
+	if !ok && IsShared(f.Proto.GetName()) {
+		for _, other := range slices.Sorted(maps.Keys(v)) {
+			val, ok = v[other][f.Proto.GetName()]
+			if ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+
+	return NewEntry(f.Proto.GetName(), NewValidatedValFromField(val, f.Proto)), true
+}
@@ -0,0 +1,167 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skiff-sh/config/ptr"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
+
+	"github.com/skiff-sh/skiff/pkg/fields"
+)
+
+type ValuesTestSuite struct {
+	suite.Suite
+}
+
+func (v *ValuesTestSuite) TestResolve() {
+	type test struct {
+		Given            string
+		Expected         Values
+		ExpectedParseErr string
+		ExpectedErr      []string
+	}
+
+	sch, err := NewSchema(&v1alpha1.Schema{Fields: []*v1alpha1.Field{
+		{Name: "name", Type: ptr.Ptr(v1alpha1.Field_string)},
+		{Name: "port", Type: ptr.Ptr(v1alpha1.Field_number)},
+		{Name: "tls", Type: ptr.Ptr(v1alpha1.Field_bool)},
+		{Name: "method", Type: ptr.Ptr(v1alpha1.Field_string), Enum: fields.NewListValue("GET", "POST")},
+		{
+			Name:  "ports",
+			Type:  ptr.Ptr(v1alpha1.Field_array),
+			Items: &v1alpha1.Field_SubField{Type: ptr.Ptr(v1alpha1.Field_number)},
+		},
+		{
+			Name:  "tags",
+			Type:  ptr.Ptr(v1alpha1.Field_array),
+			Items: &v1alpha1.Field_SubField{Type: ptr.Ptr(v1alpha1.Field_string)},
+		},
+	}})
+	if !v.NoError(err) {
+		return
+	}
+	pkgFields := map[string][]*Field{"pkg": sch.Fields}
+
+	tests := map[string]test{
+		"yaml": {
+			Given: "pkg:\n  name: api\n  port: 8080\n  tls: true\n  method: GET\n" +
+				"  ports: [80, 443.5]\n  tags: [a, b]\n",
+			Expected: Values{"pkg": {
+				"name":   "api",
+				"port":   8080.0,
+				"tls":    true,
+				"method": "GET",
+				"ports":  []float64{80, 443.5},
+				"tags":   []string{"a", "b"},
+			}},
+		},
+		"json": {
+			Given:    `{"pkg": {"port": 1, "ports": []}}`,
+			Expected: Values{"pkg": {"port": 1.0, "ports": []float64{}}},
+		},
+		"empty package": {
+			Given:    "pkg:\n",
+			Expected: Values{},
+		},
+		"errors have paths": {
+			Given: "pkg:\n  nme: api\n  port: abc\n  method: PUT\n  ports: [1, a]\n  tags: a\nother:\n  name: a\n",
+			ExpectedErr: []string{
+				"other: unknown package",
+				"pkg.method: method cannot be 'PUT': expected one of GET, POST",
+				"pkg.nme: unknown field",
+				"pkg.port: port cannot be 'abc': must be a number",
+				"pkg.ports: ports cannot be 'a': must be a number",
+				"pkg.tags: tags must be a list",
+			},
+		},
+		"package is not a map": {
+			Given:            "pkg: [a]\n",
+			ExpectedParseErr: "pkg: expected a map of field names to values",
+		},
+	}
+
+	for desc, t := range tests {
+		v.Run(desc, func() {
+			vals, err := ParseValues([]byte(t.Given))
+			if t.ExpectedParseErr != "" || !v.NoError(err) {
+				v.ErrorContains(err, t.ExpectedParseErr)
+				return
+			}
+
+			err = vals.Resolve(pkgFields)
+			if len(t.ExpectedErr) > 0 || !v.NoError(err) {
+				v.EqualError(err, strings.Join(t.ExpectedErr, "\n"))
+				return
+			}
+
+			v.Equal(t.Expected, vals)
+		})
+	}
+}
+
+func (v *ValuesTestSuite) TestMerge() {
+	actual := Values{"a": {"name": "a", "port": 1.0}}
+	actual.Merge(Values{"a": {"port": 2.0}, "b": {"name": "b"}})
+
+	v.Equal(Values{"a": {"name": "a", "port": 2.0}, "b": {"name": "b"}}, actual)
+}
+
+func (v *ValuesTestSuite) TestEntry() {
+	sch, err := NewSchema(&v1alpha1.Schema{Fields: []*v1alpha1.Field{
+		{Name: "name", Type: ptr.Ptr(v1alpha1.Field_string)},
+		{Name: "project.module", Type: ptr.Ptr(v1alpha1.Field_string)},
+	}})
+	if !v.NoError(err) {
+		return
+	}
+	name, module := sch.Fields[0], sch.Fields[1]
+
+	vals := Values{"a": {"name": "a"}, "b": {"project.module": "mod"}}
+
+	_, ok := vals.Entry("b", name)
+	v.False(ok, "package fields are not shared")
+
+	e, ok := vals.Entry("a", module)
+	if v.True(ok, "shared fields can be provided under any package") {
+		v.Equal("mod", e.Value().Any())
+	}
+}
+
+func (v *ValuesTestSuite) TestCheckShared() {
+	type test struct {
+		Given       Values
+		ExpectedErr string
+	}
+
+	tests := map[string]test{
+		"same values": {
+			Given: Values{"a": {"project.module": "mod", "name": "a"}, "b": {"project.module": "mod", "name": "b"}},
+		},
+		"different values": {
+			Given:       Values{"a": {"project.module": "a"}, "b": {"project.module": "b"}},
+			ExpectedErr: "b.project.module: shared field has a different value under a",
+		},
+		"different lists": {
+			Given:       Values{"a": {"project.tags": []string{"x"}}, "b": {"project.tags": []string{"y"}}},
+			ExpectedErr: "b.project.tags: shared field has a different value under a",
+		},
+	}
+
+	for desc, t := range tests {
+		v.Run(desc, func() {
+			err := t.Given.CheckShared()
+			if t.ExpectedErr == "" {
+				v.NoError(err)
+			} else {
+				v.EqualError(err, t.ExpectedErr)
+			}
+		})
+	}
+}
+
+func TestValuesTestSuite(t *testing.T) {
+	suite.Run(t, new(ValuesTestSuite))
+}
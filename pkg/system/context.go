@@ -0,0 +1,217 @@
+package system
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/skiff-sh/api/go/skiff/registry/v1alpha1"
+)
+
+// ContextProvider detects read-only values about the user's project e.g. the Go module path.
+type ContextProvider interface {
+	// Name of the provider. Every value is keyed by the name e.g. go.module.
+	Name() string
+	// Perms required to run the provider. If empty, the provider always runs.
+	Perms() []v1alpha1.PackagePermissions_Plugin
+	// Provide returns the detected values within the project root keyed by name. Values that can't be detected are
+	// omitted.
+	Provide(root string) map[string]string
+}
+
+// DefaultContextProviders returns all built-in providers.
+func DefaultContextProviders() []ContextProvider {
+	return []ContextProvider{
+		&osProvider{},
+		&projectProvider{},
+		&goProvider{},
+		&gitProvider{},
+	}
+}
+
+var _ ContextProvider = (*osProvider)(nil)
+
+type osProvider struct{}
+
+func (o *osProvider) Name() string {
+	return "os"
+}
+
+func (o *osProvider) Perms() []v1alpha1.PackagePermissions_Plugin {
+	return nil
+}
+
+func (o *osProvider) Provide(_ string) map[string]string {
+	return map[string]string{
+		"name": runtime.GOOS,
+		"arch": runtime.GOARCH,
+	}
+}
+
+var _ ContextProvider = (*projectProvider)(nil)
+
+type projectProvider struct{}
+
+func (p *projectProvider) Name() string {
+	return "project"
+}
+
+func (p *projectProvider) Perms() []v1alpha1.PackagePermissions_Plugin {
+	return []v1alpha1.PackagePermissions_Plugin{v1alpha1.PackagePermissions_cwd_ro}
+}
+
+func (p *projectProvider) Provide(root string) map[string]string {
+	return map[string]string{"name": filepath.Base(root)}
+}
+
+var _ ContextProvider = (*goProvider)(nil)
+
+type goProvider struct{}
+
+func (g *goProvider) Name() string {
+	return "go"
+}
+
+func (g *goProvider) Perms() []v1alpha1.PackagePermissions_Plugin {
+	return []v1alpha1.PackagePermissions_Plugin{v1alpha1.PackagePermissions_cwd_ro}
+}
+
+// Provide reads the go.mod within root. Parents of root aren't searched since cwd_ro only grants access to root.
+func (g *goProvider) Provide(root string) map[string]string {
+	b, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil
+	}
+
+	out := map[string]string{}
+	for line := range strings.Lines(string(b)) {
+		line, _, _ = strings.Cut(line, "//")
+		f := strings.Fields(line)
+		if len(f) != 2 {
+			continue
+		}
+
+		switch f[0] {
+		case "module":
+			out["module"] = strings.Trim(f[1], `"`)
+		case "go":
+			out["version"] = f[1]
+		}
+	}
+	return out
+}
+
+var _ ContextProvider = (*gitProvider)(nil)
+
+type gitProvider struct{}
+
+func (g *gitProvider) Name() string {
+	return "git"
+}
+
+func (g *gitProvider) Perms() []v1alpha1.PackagePermissions_Plugin {
+	return []v1alpha1.PackagePermissions_Plugin{v1alpha1.PackagePermissions_cwd_ro}
+}
+
+// Provide reads the git directory of the repository at root directly so git doesn't need to be installed. Parents of
+// root aren't searched since cwd_ro only grants access to root.
+func (g *gitProvider) Provide(root string) map[string]string {
+	gitDir, commonDir, ok := findGitDir(root)
+	if !ok {
+		return nil
+	}
+
+	out := map[string]string{}
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err == nil {
+		branch, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: refs/heads/")
+		if ok {
+			out["branch"] = branch
+		}
+	}
+
+	conf, err := os.ReadFile(filepath.Join(commonDir, "config"))
+	if err == nil {
+		if remote := originURL(conf); remote != "" {
+			out["remote"] = remote
+		}
+	}
+
+	return out
+}
+
+// findGitDir returns the git directory of the repository at root, and the directory shared by all of its worktrees
+// which holds the config. A .git file (worktrees and submodules) points to the git directory with a gitdir line.
+func findGitDir(root string) (gitDir, commonDir string, ok bool) {
+	fp := filepath.Join(root, ".git")
+	info, err := os.Stat(fp)
+	if err != nil {
+		return "", "", false
+	}
+
+	gitDir = fp
+	if !info.IsDir() {
+		b, err := os.ReadFile(fp)
+		if err != nil {
+			return "", "", false
+		}
+
+		dir, ok := strings.CutPrefix(strings.TrimSpace(string(b)), "gitdir:")
+		if !ok {
+			return "", "", false
+		}
+
+		gitDir = resolvePath(filepath.Dir(fp), strings.TrimSpace(dir))
+	}
+
+	commonDir = gitDir
+	b, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err == nil {
+		commonDir = resolvePath(gitDir, strings.TrimSpace(string(b)))
+	}
+
+	return gitDir, commonDir, true
+}
+
+// resolvePath resolves fp relative to dir unless it's absolute.
+func resolvePath(dir, fp string) string {
+	if filepath.IsAbs(fp) {
+		return fp
+	}
+	return filepath.Join(dir, fp)
+}
+
+// originURL returns the url of the origin remote within a git config file without any credentials.
+func originURL(conf []byte) string {
+	inOrigin := false
+	for line := range strings.Lines(string(conf)) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+
+		key, val, ok := strings.Cut(line, "=")
+		if inOrigin && ok && strings.TrimSpace(key) == "url" {
+			return redactURL(strings.TrimSpace(val))
+		}
+	}
+	return ""
+}
+
+// redactURL removes the user info from a remote url e.g. https://x-access-token:<token>@github.com/org/repo. SCP-like
+// urls e.g. git@github.com:org/repo.git are kept since they can't hold a password. Returns empty if the url is invalid.
+func redactURL(raw string) string {
+	if !strings.Contains(raw, "://") {
+		return raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	return u.String()
+}
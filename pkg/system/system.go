@@ -40,6 +40,9 @@ var initer = sync.OnceValue[error](func() error {
 type System interface {
 	// CWD returns the user's current working directory. If empty, permission was not granted.
 	CWD() string
+	// Context returns the values of every ContextProvider the policy grants keyed by provider and value name e.g.
+	// go.module.
+	Context() map[string]string
 }
 
 // Mediator provides the capabilities of the System but mediated by a policy.
@@ -47,25 +50,67 @@ type Mediator interface {
 	MediatedSystem(policy *accesscontrol.PluginAccessPolicy) System
 }
 
-// NewMediator constructor for Mediator.
-func NewMediator() Mediator {
-	out := &mediator{}
+// NewMediator constructor for Mediator. The providers detect values within the root. Each provider is only run once.
+func NewMediator(root string, providers ...ContextProvider) Mediator {
+	out := &mediator{
+		Providers: make([]*contextProvider, 0, len(providers)),
+	}
+
+	for _, v := range providers {
+		out.Providers = append(out.Providers, &contextProvider{
+			Provider: v,
+			Values: sync.OnceValue(func() map[string]string {
+				return v.Provide(root)
+			}),
+		})
+	}
 
 	return out
 }
 
 type mediator struct {
+	Providers []*contextProvider
+}
+
+type contextProvider struct {
+	Provider ContextProvider
+	Values   func() map[string]string
 }
 
 func (m *mediator) MediatedSystem(policy *accesscontrol.PluginAccessPolicy) System {
 	return &system{
-		Policy: policy,
+		Policy:    policy,
+		Providers: m.Providers,
 	}
 }
 
 type system struct {
 	Policy     *accesscontrol.PluginAccessPolicy
 	WorkingDir string
+	Providers  []*contextProvider
+}
+
+func (s *system) Context() map[string]string {
+	out := map[string]string{}
+	for _, v := range s.Providers {
+		if !s.authorized(v.Provider.Perms()) {
+			continue
+		}
+
+		for k, val := range v.Values() {
+			out[v.Provider.Name()+"."+k] = val
+		}
+	}
+	return out
+}
+
+func (s *system) authorized(perms []v1alpha1.PackagePermissions_Plugin) bool {
+	for _, v := range perms {
+		if !s.Policy.Authorize(v) {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *system) CWD() string {
@@ -0,0 +1,475 @@
+package tmpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// FuncMap returns the functions available to every Go template. Functions that operate on a single value take it as
+// their last argument so they can be used in pipelines e.g. {{ .name | snakeCase | upper }}.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		// Casing.
+		"lower":              strings.ToLower,
+		"upper":              strings.ToUpper,
+		"first":              first,
+		"capitalize":         capitalize,
+		"camelCase":          CamelCase,
+		"pascalCase":         PascalCase,
+		"snakeCase":          SnakeCase,
+		"kebabCase":          KebabCase,
+		"screamingSnakeCase": ScreamingSnakeCase,
+		"goIdent":            GoIdent,
+
+		// Inflection.
+		"pluralize":   Pluralize,
+		"singularize": Singularize,
+
+		// Whitespace and strings.
+		"indent":     indent,
+		"nindent":    nindent,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": trimPrefix,
+		"trimSuffix": trimSuffix,
+		"replace":    replace,
+		"contains":   contains,
+		"hasPrefix":  hasPrefix,
+		"hasSuffix":  hasSuffix,
+
+		// Regex.
+		"regexMatch":      regexMatch,
+		"regexFind":       regexFind,
+		"regexReplaceAll": regexReplaceAll,
+
+		// Lists.
+		"join":  join,
+		"split": split,
+		"list":  list,
+
+		// Defaults and conditionals.
+		"default":  defaultVal,
+		"ternary":  ternary,
+		"coalesce": coalesce,
+		"empty":    empty,
+
+		// Encoding.
+		"toJson":       toJSON,
+		"toPrettyJson": toPrettyJSON,
+		"toYaml":       toYAML,
+	}
+}
+
+// first returns the first character of s.
+func first(s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return string([]rune(s)[0])
+}
+
+// capitalize upper cases the first letter of s and leaves the rest untouched.
+func capitalize(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+
+	return caser.String(s[:1]) + s[1:]
+}
+
+// CamelCase converts s to camelCase e.g. "http route" -> "httpRoute".
+func CamelCase(s string) string {
+	w := words(s)
+	for i := range w {
+		if i == 0 {
+			w[i] = strings.ToLower(w[i])
+		} else {
+			w[i] = titleWord(w[i])
+		}
+	}
+	return strings.Join(w, "")
+}
+
+// PascalCase converts s to PascalCase e.g. "http route" -> "HttpRoute".
+func PascalCase(s string) string {
+	w := words(s)
+	for i := range w {
+		w[i] = titleWord(w[i])
+	}
+	return strings.Join(w, "")
+}
+
+// SnakeCase converts s to snake_case e.g. "HTTPRoute" -> "http_route".
+func SnakeCase(s string) string {
+	return strings.ToLower(strings.Join(words(s), "_"))
+}
+
+// KebabCase converts s to kebab-case e.g. "HTTPRoute" -> "http-route".
+func KebabCase(s string) string {
+	return strings.ToLower(strings.Join(words(s), "-"))
+}
+
+// ScreamingSnakeCase converts s to SCREAMING_SNAKE_CASE e.g. "httpRoute" -> "HTTP_ROUTE".
+func ScreamingSnakeCase(s string) string {
+	return strings.ToUpper(strings.Join(words(s), "_"))
+}
+
+// GoIdent sanitizes s into a valid Go identifier. Invalid characters are replaced with underscores, a leading digit is
+// prefixed with an underscore, and Go keywords are suffixed with an underscore.
+func GoIdent(s string) string {
+	if s == "" {
+		return "_"
+	}
+
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+			b.WriteRune(r)
+		case unicode.IsDigit(r):
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	out := b.String()
+	if token.IsKeyword(out) {
+		out += "_"
+	}
+	return out
+}
+
+// words splits s into its component words. Words are separated by any non-alphanumeric character and by case
+// boundaries e.g. "HTTPServer_name" -> ["HTTP", "Server", "name"].
+func words(s string) []string {
+	var out []string
+	runes := []rune(s)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				out = append(out, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		boundary := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev))
+		// Handles acronyms e.g. the "S" in "HTTPServer".
+		if !boundary && unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) {
+			boundary = unicode.IsLower(runes[i+1])
+		}
+
+		if boundary {
+			out = append(out, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		out = append(out, string(runes[start:]))
+	}
+
+	return out
+}
+
+func titleWord(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(strings.ToLower(s))
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// indent prefixes every line of s with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+// nindent is the same as indent but prepends a newline.
+func nindent(n int, s string) string {
+	return "\n" + indent(n, s)
+}
+
+func trimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+func trimSuffix(suffix, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+func replace(old, n, s string) string {
+	return strings.ReplaceAll(s, old, n)
+}
+
+func contains(substr, s string) bool {
+	return strings.Contains(s, substr)
+}
+
+func hasPrefix(prefix, s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+func hasSuffix(suffix, s string) bool {
+	return strings.HasSuffix(s, suffix)
+}
+
+func regexMatch(expr, s string) (bool, error) {
+	return regexp.MatchString(expr, s)
+}
+
+func regexFind(expr, s string) (string, error) {
+	r, err := regexp.Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	return r.FindString(s), nil
+}
+
+func regexReplaceAll(expr, repl, s string) (string, error) {
+	r, err := regexp.Compile(expr)
+	if err != nil {
+		return "", err
+	}
+	return r.ReplaceAllString(s, repl), nil
+}
+
+// join joins the elements of any list (e.g. an array field) with sep.
+func join(sep string, l any) (string, error) {
+	items, err := toList(l)
+	if err != nil {
+		return "", err
+	}
+
+	strs := make([]string, 0, len(items))
+	for _, v := range items {
+		strs = append(strs, fmt.Sprint(v))
+	}
+	return strings.Join(strs, sep), nil
+}
+
+// split splits s by sep. An empty s results in an empty list.
+func split(sep, s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, sep)
+}
+
+func list(items ...any) []any {
+	return items
+}
+
+func toList(l any) ([]any, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	if v, ok := l.([]any); ok {
+		return v, nil
+	}
+
+	val := reflect.ValueOf(l)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a list but got %T", l)
+	}
+
+	out := make([]any, 0, val.Len())
+	for i := range val.Len() {
+		out = append(out, val.Index(i).Interface())
+	}
+	return out, nil
+}
+
+// defaultVal returns def if val is empty.
+func defaultVal(def, val any) any {
+	if empty(val) {
+		return def
+	}
+	return val
+}
+
+// ternary returns a if cond is true, otherwise b.
+func ternary(a, b any, cond bool) any {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// coalesce returns the first non-empty value.
+func coalesce(vals ...any) any {
+	for _, v := range vals {
+		if !empty(v) {
+			return v
+		}
+	}
+	return nil
+}
+
+// empty returns true if v is nil or the zero value of its type. Empty lists and maps are also considered empty.
+func empty(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	val := reflect.ValueOf(v)
+	//nolint:exhaustive // everything else is checked for its zero value.
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return val.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return val.IsNil()
+	}
+	return val.IsZero()
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+func toPrettyJSON(v any) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	return string(b), err
+}
+
+// Indentation of YAML produced by toYaml. Matches the convention of Helm and Kubernetes files.
+const yamlIndent = 2
+
+func toYAML(v any) (string, error) {
+	var sb strings.Builder
+	enc := yaml.NewEncoder(&sb)
+	enc.SetIndent(yamlIndent)
+	err := enc.Encode(v)
+	if err != nil {
+		return "", err
+	}
+
+	err = enc.Close()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// Pluralize returns the plural form of an English word e.g. "route" -> "routes", "policy" -> "policies".
+func Pluralize(s string) string {
+	return inflect(s, irregularPlurals, pluralRules)
+}
+
+// Singularize returns the singular form of an English word e.g. "routes" -> "route", "policies" -> "policy".
+func Singularize(s string) string {
+	return inflect(s, irregularSingulars, singularRules)
+}
+
+// fWords the stems of words ending in f whose plural ends in ves e.g. shelf and shelves.
+const fWords = "cal|hal|el|wol|lea|loa|thie|shea|dwar"
+
+type inflection struct {
+	Match   *regexp.Regexp
+	Replace string
+}
+
+var (
+	uncountables = map[string]bool{
+		"data": true, "equipment": true, "information": true, "info": true, "metadata": true, "news": true,
+		"series": true, "sheep": true, "species": true, "fish": true, "deer": true, "config": true,
+	}
+
+	irregularPlurals = map[string]string{
+		"person": "people", "child": "children", "man": "men", "woman": "women", "mouse": "mice",
+		"goose": "geese", "foot": "feet", "tooth": "teeth", "ox": "oxen", "index": "indices",
+		"matrix": "matrices", "vertex": "vertices", "criterion": "criteria",
+	}
+
+	irregularSingulars = invert(irregularPlurals)
+
+	// Rules are evaluated in order and the first match wins. Irregular endings are only handled for known words since
+	// the general rules are wrong for as many words as they're right e.g. shelf and golf, or niches and matches.
+	pluralRules = []inflection{
+		{regexp.MustCompile(`(?i)(quiz)$`), "${1}zes"},
+		{regexp.MustCompile(`(?i)([^aeiouy]|qu)y$`), "${1}ies"},
+		{regexp.MustCompile(`(?i)(` + fWords + `)f$`), "${1}ves"},
+		{regexp.MustCompile(`(?i)(kni|wi|li)fe$`), "${1}ves"},
+		{regexp.MustCompile(`(?i)([sx])is$`), "${1}es"},
+		{regexp.MustCompile(`(?i)(x|ch|ss|sh|s|z)$`), "${1}es"},
+		{regexp.MustCompile(`(?i)(buffal|tomat|potat|her)o$`), "${1}oes"},
+		{regexp.MustCompile(`$`), "s"},
+	}
+
+	singularRules = []inflection{
+		{regexp.MustCompile(`(?i)(quiz)zes$`), "${1}"},
+		// Words ending in ie which would otherwise become y e.g. movies. Short words must be whole words so parties
+		// isn't partie.
+		{regexp.MustCompile(`(?i)(movie|cookie|zombie|rookie|goalie|calorie|prairie|selfie|smoothie|\b[dlpt]ie)s$`), "${1}"},
+		{regexp.MustCompile(`(?i)([^aeiouy]|qu)ies$`), "${1}y"},
+		{regexp.MustCompile(`(?i)(` + fWords + `)ves$`), "${1}f"},
+		{regexp.MustCompile(`(?i)\b(kni|wi|li)ves$`), "${1}fe"},
+		// Words ending in che which would otherwise lose the e e.g. caches.
+		{regexp.MustCompile(`(?i)(\bache|cache|headache|moustache|niche|cliche|avalanche)s$`), "${1}"},
+		{regexp.MustCompile(`(?i)(ias)es$`), "${1}"},
+		{regexp.MustCompile(`(?i)(analy|diagno|parenthe|synop|\bthe|hypothe|empha|progno|synthe|\bcri|\boa)ses$`), "${1}sis"},
+		// Only known words ending in us since most words ending in uses end in use e.g. houses.
+		{regexp.MustCompile(`(?i)(\bb|stat|vir|camp|bon|cens|foc|nex|syllab|apparat|prospect)uses$`), "${1}us"},
+		{regexp.MustCompile(`(?i)(x|ch|ss|sh|z)es$`), "${1}"},
+		{regexp.MustCompile(`(?i)(buffal|tomat|potat|her)oes$`), "${1}o"},
+		{regexp.MustCompile(`(?i)(ss|us|is)$`), "${1}"},
+		{regexp.MustCompile(`(?i)s$`), ""},
+	}
+)
+
+func inflect(s string, irregulars map[string]string, rules []inflection) string {
+	lower := strings.ToLower(s)
+	if s == "" || uncountables[lower] {
+		return s
+	}
+
+	if v, ok := irregulars[lower]; ok {
+		return matchCase(s, v)
+	}
+
+	for _, v := range rules {
+		if v.Match.MatchString(s) {
+			return v.Match.ReplaceAllString(s, v.Replace)
+		}
+	}
+
+	return s
+}
+
+// matchCase applies the capitalization of the first letter of src to s.
+func matchCase(src, s string) string {
+	if unicode.IsUpper([]rune(src)[0]) {
+		return titleWord(s)
+	}
+	return s
+}
+
+func invert(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[v] = k
+	}
+	return out
+}
@@ -0,0 +1,204 @@
+package tmpl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FuncsTestSuite struct {
+	suite.Suite
+}
+
+type funcTest struct {
+	Given       string
+	Data        map[string]any
+	Expected    string
+	ExpectedErr string
+}
+
+func (f *FuncsTestSuite) run(tests map[string]funcTest) {
+	for desc, v := range tests {
+		f.Run(desc, func() {
+			t, err := NewGoFactory().NewTemplate([]byte(v.Given))
+			if !f.NoError(err) {
+				return
+			}
+
+			buf := bytes.NewBuffer(nil)
+			err = t.Render(v.Data, buf)
+			if v.ExpectedErr != "" || !f.NoError(err) {
+				f.ErrorContains(err, v.ExpectedErr)
+				return
+			}
+
+			f.Equal(v.Expected, buf.String())
+		})
+	}
+}
+
+func (f *FuncsTestSuite) TestCasing() {
+	f.run(map[string]funcTest{
+		"lower":                   {Given: `{{ lower "HeLLo" }}`, Expected: "hello"},
+		"upper":                   {Given: `{{ upper "hello" }}`, Expected: "HELLO"},
+		"first":                   {Given: `{{ first "hello" }}`, Expected: "h"},
+		"first empty":             {Given: `{{ first "" }}`, Expected: ""},
+		"capitalize":              {Given: `{{ capitalize "helloWorld" }}`, Expected: "HelloWorld"},
+		"camel from spaces":       {Given: `{{ camelCase "http route" }}`, Expected: "httpRoute"},
+		"camel from pascal":       {Given: `{{ camelCase "HTTPServer" }}`, Expected: "httpServer"},
+		"camel from snake":        {Given: `{{ camelCase "user_id" }}`, Expected: "userId"},
+		"pascal from kebab":       {Given: `{{ pascalCase "create-http-route" }}`, Expected: "CreateHttpRoute"},
+		"pascal from camel":       {Given: `{{ pascalCase "myController2Name" }}`, Expected: "MyController2Name"},
+		"snake from pascal":       {Given: `{{ snakeCase "HTTPRoute" }}`, Expected: "http_route"},
+		"snake from camel":        {Given: `{{ snakeCase "userID" }}`, Expected: "user_id"},
+		"kebab from snake":        {Given: `{{ kebabCase "my_http_route" }}`, Expected: "my-http-route"},
+		"kebab from mixed":        {Given: `{{ kebabCase "My HTTP.route" }}`, Expected: "my-http-route"},
+		"screaming snake":         {Given: `{{ screamingSnakeCase "httpRoute" }}`, Expected: "HTTP_ROUTE"},
+		"screaming snake spaces":  {Given: `{{ screamingSnakeCase " max  retries " }}`, Expected: "MAX_RETRIES"},
+		"pipeline":                {Given: `{{ .name | snakeCase | upper }}`, Data: map[string]any{"name": "fooBar"}, Expected: "FOO_BAR"},
+		"go ident":                {Given: `{{ goIdent "my-name" }}`, Expected: "my_name"},
+		"go ident leading digit":  {Given: `{{ goIdent "1st" }}`, Expected: "_1st"},
+		"go ident keyword":        {Given: `{{ goIdent "type" }}`, Expected: "type_"},
+		"go ident empty":          {Given: `{{ goIdent "" }}`, Expected: "_"},
+		"go ident already valid":  {Given: `{{ goIdent "Controller_2" }}`, Expected: "Controller_2"},
+		"go ident with pascal":    {Given: `{{ "1 http route" | pascalCase | goIdent }}`, Expected: "_1HttpRoute"},
+		"go ident unicode letter": {Given: `{{ goIdent "héllo wörld" }}`, Expected: "héllo_wörld"},
+	})
+}
+
+func (f *FuncsTestSuite) TestInflection() {
+	f.run(map[string]funcTest{
+		"pluralize regular":       {Given: `{{ pluralize "route" }}`, Expected: "routes"},
+		"pluralize y":             {Given: `{{ pluralize "policy" }}`, Expected: "policies"},
+		"pluralize vowel y":       {Given: `{{ pluralize "key" }}`, Expected: "keys"},
+		"pluralize es":            {Given: `{{ pluralize "box" }}`, Expected: "boxes"},
+		"pluralize ch":            {Given: `{{ pluralize "match" }}`, Expected: "matches"},
+		"pluralize f":             {Given: `{{ pluralize "shelf" }}`, Expected: "shelves"},
+		"pluralize irregular":     {Given: `{{ pluralize "Person" }}`, Expected: "People"},
+		"pluralize uncountable":   {Given: `{{ pluralize "data" }}`, Expected: "data"},
+		"pluralize empty":         {Given: `{{ pluralize "" }}`, Expected: ""},
+		"singularize regular":     {Given: `{{ singularize "routes" }}`, Expected: "route"},
+		"singularize ies":         {Given: `{{ singularize "policies" }}`, Expected: "policy"},
+		"singularize es":          {Given: `{{ singularize "boxes" }}`, Expected: "box"},
+		"singularize ves":         {Given: `{{ singularize "shelves" }}`, Expected: "shelf"},
+		"singularize irregular":   {Given: `{{ singularize "children" }}`, Expected: "child"},
+		"singularize status":      {Given: `{{ singularize "status" }}`, Expected: "status"},
+		"singularize statuses":    {Given: `{{ singularize "statuses" }}`, Expected: "status"},
+		"singularize uncountable": {Given: `{{ singularize "series" }}`, Expected: "series"},
+		"singularize buses":       {Given: `{{ singularize "buses" }}`, Expected: "bus"},
+		"singularize houses":      {Given: `{{ singularize "houses" }}`, Expected: "house"},
+		"singularize causes":      {Given: `{{ singularize "causes" }}`, Expected: "cause"},
+		"singularize uses":        {Given: `{{ singularize "uses" }}`, Expected: "use"},
+		"singularize abuses":      {Given: `{{ singularize "abuses" }}`, Expected: "abuse"},
+		"singularize movies":      {Given: `{{ singularize "movies" }}`, Expected: "movie"},
+		"singularize cookies":     {Given: `{{ singularize "Cookies" }}`, Expected: "Cookie"},
+		"singularize ties":        {Given: `{{ singularize "ties" }}`, Expected: "tie"},
+		"singularize parties":     {Given: `{{ singularize "parties" }}`, Expected: "party"},
+		"singularize caches":      {Given: `{{ singularize "caches" }}`, Expected: "cache"},
+		"singularize matches":     {Given: `{{ singularize "matches" }}`, Expected: "match"},
+		"singularize aliases":     {Given: `{{ singularize "aliases" }}`, Expected: "alias"},
+		"singularize analyses":    {Given: `{{ singularize "analyses" }}`, Expected: "analysis"},
+		"singularize databases":   {Given: `{{ singularize "databases" }}`, Expected: "database"},
+		"singularize valves":      {Given: `{{ singularize "valves" }}`, Expected: "valve"},
+		"singularize curves":      {Given: `{{ singularize "curves" }}`, Expected: "curve"},
+		"singularize nerves":      {Given: `{{ singularize "nerves" }}`, Expected: "nerve"},
+		"singularize wolves":      {Given: `{{ singularize "wolves" }}`, Expected: "wolf"},
+		"singularize knives":      {Given: `{{ singularize "knives" }}`, Expected: "knife"},
+		"singularize olives":      {Given: `{{ singularize "olives" }}`, Expected: "olive"},
+		"pluralize safe":          {Given: `{{ pluralize "safe" }}`, Expected: "safes"},
+		"pluralize cafe":          {Given: `{{ pluralize "cafe" }}`, Expected: "cafes"},
+		"pluralize golf":          {Given: `{{ pluralize "golf" }}`, Expected: "golfs"},
+		"pluralize wolf":          {Given: `{{ pluralize "wolf" }}`, Expected: "wolves"},
+		"pluralize knife":         {Given: `{{ pluralize "knife" }}`, Expected: "knives"},
+		"pluralize analysis":      {Given: `{{ pluralize "analysis" }}`, Expected: "analyses"},
+		"pluralize axis":          {Given: `{{ pluralize "axis" }}`, Expected: "axes"},
+		"pluralize cache":         {Given: `{{ pluralize "cache" }}`, Expected: "caches"},
+		"pluralize alias":         {Given: `{{ pluralize "alias" }}`, Expected: "aliases"},
+		"pluralize status":        {Given: `{{ pluralize "status" }}`, Expected: "statuses"},
+	})
+}
+
+func (f *FuncsTestSuite) TestStrings() {
+	f.run(map[string]funcTest{
+		"indent":              {Given: `{{ indent 2 "a\nb" }}`, Expected: "  a\n  b"},
+		"nindent":             {Given: `key:{{ nindent 2 "a: 1\nb: 2" }}`, Expected: "key:\n  a: 1\n  b: 2"},
+		"trim":                {Given: `{{ trim "  a  " }}`, Expected: "a"},
+		"trim prefix":         {Given: `{{ "v1.2" | trimPrefix "v" }}`, Expected: "1.2"},
+		"trim suffix":         {Given: `{{ "main.go" | trimSuffix ".go" }}`, Expected: "main"},
+		"replace":             {Given: `{{ "a.b.c" | replace "." "/" }}`, Expected: "a/b/c"},
+		"contains":            {Given: `{{ if contains "b" "abc" }}yes{{ end }}`, Expected: "yes"},
+		"has prefix":          {Given: `{{ if hasPrefix "/api" "/api/v1" }}yes{{ end }}`, Expected: "yes"},
+		"has suffix":          {Given: `{{ if hasSuffix ".go" "main.go" }}yes{{ end }}`, Expected: "yes"},
+		"regex match":         {Given: `{{ regexMatch "^[a-z]+$" "abc" }}`, Expected: "true"},
+		"regex no match":      {Given: `{{ regexMatch "^[a-z]+$" "ab1" }}`, Expected: "false"},
+		"regex find":          {Given: `{{ regexFind "[0-9]+" "v12.3" }}`, Expected: "12"},
+		"regex replace":       {Given: `{{ "/users/:id" | regexReplaceAll ":([a-z]+)" "{$1}" }}`, Expected: "/users/{id}"},
+		"regex invalid":       {Given: `{{ regexFind "(" "abc" }}`, ExpectedErr: "missing closing )"},
+		"regex match invalid": {Given: `{{ regexMatch "(" "abc" }}`, ExpectedErr: "missing closing )"},
+	})
+}
+
+func (f *FuncsTestSuite) TestLists() {
+	f.run(map[string]funcTest{
+		"join strings":   {Given: `{{ join ", " .names }}`, Data: map[string]any{"names": []string{"a", "b"}}, Expected: "a, b"},
+		"join any":       {Given: `{{ join "," .names }}`, Data: map[string]any{"names": []any{"a", 1.5}}, Expected: "a,1.5"},
+		"join numbers":   {Given: `{{ .nums | join "-" }}`, Data: map[string]any{"nums": []float64{1, 2}}, Expected: "1-2"},
+		"join nil":       {Given: `{{ join "," .missing }}`, Data: map[string]any{}, Expected: ""},
+		"join not list":  {Given: `{{ join "," .name }}`, Data: map[string]any{"name": "a"}, ExpectedErr: "expected a list but got string"},
+		"split":          {Given: `{{ range split "," "a,b" }}[{{ . }}]{{ end }}`, Expected: "[a][b]"},
+		"split empty":    {Given: `{{ len (split "," "") }}`, Expected: "0"},
+		"split and join": {Given: `{{ split "." "a.b.c" | join "/" }}`, Expected: "a/b/c"},
+		"list":           {Given: `{{ list "a" "b" | join "" }}`, Expected: "ab"},
+	})
+}
+
+func (f *FuncsTestSuite) TestDefaults() {
+	f.run(map[string]funcTest{
+		"default missing":      {Given: `{{ .port | default 8080 }}`, Data: map[string]any{}, Expected: "8080"},
+		"default empty string": {Given: `{{ .name | default "app" }}`, Data: map[string]any{"name": ""}, Expected: "app"},
+		"default set":          {Given: `{{ .name | default "app" }}`, Data: map[string]any{"name": "api"}, Expected: "api"},
+		"default empty list":   {Given: `{{ .names | default "none" }}`, Data: map[string]any{"names": []string{}}, Expected: "none"},
+		"ternary true":         {Given: `{{ ternary "yes" "no" true }}`, Expected: "yes"},
+		"ternary false":        {Given: `{{ .ok | ternary "yes" "no" }}`, Data: map[string]any{"ok": false}, Expected: "no"},
+		"coalesce":             {Given: `{{ coalesce .a .b "c" }}`, Data: map[string]any{"a": "", "b": "b"}, Expected: "b"},
+		"coalesce all empty":   {Given: `{{ coalesce .a .b }}`, Data: map[string]any{"a": ""}, Expected: "<no value>"},
+		"empty":                {Given: `{{ empty .a }} {{ empty .b }}`, Data: map[string]any{"a": 0.0, "b": "b"}, Expected: "true false"},
+		"empty missing":        {Given: `{{ empty .a }}`, Data: map[string]any{}, Expected: "true"},
+		"empty false and zero": {Given: `{{ empty false }} {{ empty 0 }} {{ empty 1 }}`, Expected: "true true false"},
+	})
+}
+
+func (f *FuncsTestSuite) TestEncoding() {
+	f.run(map[string]funcTest{
+		"to json": {
+			Given:    `{{ toJson . }}`,
+			Data:     map[string]any{"name": "a", "ports": []float64{80, 443}},
+			Expected: `{"name":"a","ports":[80,443]}`,
+		},
+		"to pretty json": {
+			Given:    `{{ toPrettyJson .ports }}`,
+			Data:     map[string]any{"ports": []float64{80}},
+			Expected: "[\n  80\n]",
+		},
+		"to yaml": {
+			Given:    `{{ toYaml . }}`,
+			Data:     map[string]any{"name": "a", "ports": []float64{80, 443}},
+			Expected: "name: a\nports:\n  - 80\n  - 443",
+		},
+		"to yaml nested": {
+			Given:    `{{ toYaml . }}`,
+			Data:     map[string]any{"spec": map[string]any{"template": map[string]any{"replicas": 2}}},
+			Expected: "spec:\n  template:\n    replicas: 2",
+		},
+		"to yaml nindent": {
+			Given:    `spec:{{ toYaml .spec | nindent 2 }}`,
+			Data:     map[string]any{"spec": map[string]any{"replicas": 2}},
+			Expected: "spec:\n  replicas: 2",
+		},
+	})
+}
+
+func TestFuncsTestSuite(t *testing.T) {
+	suite.Run(t, new(FuncsTestSuite))
+}
@@ -1,9 +1,16 @@
 package tmpl
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"path"
+	"slices"
 	"strings"
 	"text/template"
+	"text/template/parse"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -11,39 +18,92 @@ import (
 
 var caser = cases.Title(language.English)
 
+// maxIncludeDepth guards against cycles created through include calls with dynamic names.
+const maxIncludeDepth = 100
+
 type Template interface {
 	Render(d map[string]any, in io.Writer) error
+
+	// Each returns the list passed to {{ each .list }} when rendering with d. ok is false if each is never called.
+	// Rendering stops at the first call to each. Outside of Each, calls to each produce no output.
+	Each(d map[string]any) (items []any, ok bool, err error)
+}
+
+// errEachCalled stops execution once each has been called.
+var errEachCalled = errors.New("each called")
+
+// Engine the syntax used to parse a template.
+type Engine string
+
+const (
+	// EngineGo Go templates using {{ }} delimiters. This is the default.
+	EngineGo Engine = "go"
+
+	// EngineAngle Go templates using <% %> delimiters. Useful for files that contain {{ }} themselves e.g. Helm charts,
+	// GitHub Actions, or Jinja templates.
+	EngineAngle Engine = "angle"
+)
+
+// EngineExtensions maps file extensions to the Engine used to render them. Files with any other extension use
+// EngineGo.
+var EngineExtensions = map[string]Engine{
+	".tmpl":   EngineGo,
+	".gotmpl": EngineGo,
+	".atmpl":  EngineAngle,
+}
+
+// EngineForPath returns the Engine for a file based on its extension.
+func EngineForPath(p string) Engine {
+	e, ok := EngineExtensions[path.Ext(p)]
+	if !ok {
+		return EngineGo
+	}
+	return e
+}
+
+var goDelims = map[Engine][2]string{
+	EngineGo:    {"{{", "}}"},
+	EngineAngle: {"<%", "%>"},
 }
 
 type Factory interface {
 	NewTemplate(tmpl []byte) (Template, error)
+
+	// WithEngine returns a new Factory that shares all partials but parses templates using the syntax of e.
+	WithEngine(e Engine) (Factory, error)
+
+	// WithPartials returns a new Factory whose templates can reference every partial via {{ template "name" . }} or
+	// {{ include "name" . }}. Partials never produce output on their own. Each partial is available by its name as
+	// well as by any {{ define }} blocks within it.
+	WithPartials(partials ...*Partial) (Factory, error)
+}
+
+// Partial a named template that is shared between templates.
+type Partial struct {
+	Name    string
+	Content []byte
+	// The syntax of the partial. Defaults to EngineGo.
+	Engine Engine
 }
 
 func NewGoFactory() Factory {
-	return &goFactory{}
+	return &goFactory{Engine: EngineGo}
 }
 
 type goFactory struct {
+	// The template set housing all partials. Nil if there are none.
+	Partials *template.Template
+	Engine   Engine
 }
 
 func (g *goFactory) NewTemplate(tmpl []byte) (Template, error) {
-	t, err := template.New("").Funcs(template.FuncMap{
-		"lower": strings.ToLower,
-		"upper": strings.ToUpper,
-		"first": func(s string) string {
-			if len(s) == 0 {
-				return ""
-			}
-			return string([]rune(s)[0])
-		},
-		"capitalize": func(s string) string {
-			if len(s) == 0 {
-				return s
-			}
+	t, err := g.newRoot()
+	if err != nil {
+		return nil, err
+	}
 
-			return caser.String(s[:1]) + s[1:]
-		},
-	}).Parse(string(tmpl))
+	delims := goDelims[g.Engine]
+	t, err = t.New("").Delims(delims[0], delims[1]).Parse(string(tmpl))
 	if err != nil {
 		return nil, err
 	}
@@ -53,10 +113,229 @@ func (g *goFactory) NewTemplate(tmpl []byte) (Template, error) {
 	}, nil
 }
 
+func (g *goFactory) WithEngine(e Engine) (Factory, error) {
+	if _, ok := goDelims[e]; !ok {
+		return nil, fmt.Errorf("unknown template engine %s", e)
+	}
+
+	return &goFactory{Partials: g.Partials, Engine: e}, nil
+}
+
+func (g *goFactory) WithPartials(partials ...*Partial) (Factory, error) {
+	root, err := g.newRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range partials {
+		e := v.Engine
+		if e == "" {
+			e = EngineGo
+		}
+
+		delims, ok := goDelims[e]
+		if !ok {
+			return nil, fmt.Errorf("partial %s: unknown template engine %s", v.Name, e)
+		}
+
+		_, err = root.New(v.Name).Delims(delims[0], delims[1]).Parse(string(v.Content))
+		if err != nil {
+			return nil, fmt.Errorf("partial %s: %w", v.Name, err)
+		}
+	}
+
+	err = checkCycles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &goFactory{Partials: root, Engine: g.Engine}, nil
+}
+
+func (g *goFactory) newRoot() (*template.Template, error) {
+	if g.Partials != nil {
+		return g.Partials.Clone()
+	}
+
+	return template.New("").Funcs(FuncMap()).Funcs(template.FuncMap{
+		// Replaced on render.
+		"include": func(string, any) (string, error) {
+			return "", nil
+		},
+		"each": noopEach,
+	}), nil
+}
+
 type goTemplate struct {
 	T *template.Template
 }
 
 func (g *goTemplate) Render(d map[string]any, in io.Writer) error {
-	return g.T.Execute(in, d)
+	t, err := g.clone(noopEach)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(in, d)
+}
+
+func (g *goTemplate) Each(d map[string]any) ([]any, bool, error) {
+	var items []any
+	t, err := g.clone(func(l any) (string, error) {
+		var err error
+		items, err = toList(l)
+		if err != nil {
+			return "", err
+		}
+		return "", errEachCalled
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = t.Execute(io.Discard, d)
+	if errors.Is(err, errEachCalled) {
+		return items, true, nil
+	}
+	return nil, false, err
+}
+
+// clone returns a copy of the template with the render-time functions bound.
+func (g *goTemplate) clone(each func(l any) (string, error)) (*template.Template, error) {
+	t, err := g.T.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	var stack []string
+	t.Funcs(template.FuncMap{
+		"each": each,
+		"include": func(name string, data any) (string, error) {
+			if len(stack) >= maxIncludeDepth {
+				return "", &includeDepthError{Stack: append(stack, name)}
+			}
+
+			stack = append(stack, name)
+			defer func() {
+				stack = stack[:len(stack)-1]
+			}()
+
+			buf := bytes.NewBuffer(nil)
+			err := t.ExecuteTemplate(buf, name, data)
+			if depthErr := new(includeDepthError); errors.As(err, &depthErr) {
+				// Avoid wrapping the error at every level.
+				return "", depthErr
+			}
+			return buf.String(), err
+		},
+	})
+
+	return t, nil
+}
+
+func noopEach(any) (string, error) {
+	return "", nil
+}
+
+type includeDepthError struct {
+	Stack []string
+}
+
+func (i *includeDepthError) Error() string {
+	//nolint:mnd // not magic.
+	stack := i.Stack[:min(len(i.Stack), 5)]
+	return fmt.Sprintf("include depth exceeded: %s -> ...", strings.Join(stack, " -> "))
+}
+
+// checkCycles returns an error if any template within t references itself through {{ template }} or {{ include }}.
+func checkCycles(t *template.Template) error {
+	graph := map[string][]string{}
+	for _, v := range t.Templates() {
+		if v.Tree == nil {
+			continue
+		}
+		graph[v.Name()] = references(v.Tree.Root, nil)
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := map[string]int{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		path = append(path, name)
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("partial cycle detected: %s", strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		for _, v := range graph[name] {
+			if err := visit(v, path); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	for _, v := range slices.Sorted(maps.Keys(graph)) {
+		if err := visit(v, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// references returns the names of all templates referenced by n.
+func references(n parse.Node, out []string) []string {
+	switch typ := n.(type) {
+	case *parse.ListNode:
+		if typ == nil {
+			return out
+		}
+		for _, v := range typ.Nodes {
+			out = references(v, out)
+		}
+	case *parse.ActionNode:
+		out = references(typ.Pipe, out)
+	case *parse.IfNode:
+		out = branchReferences(&typ.BranchNode, out)
+	case *parse.RangeNode:
+		out = branchReferences(&typ.BranchNode, out)
+	case *parse.WithNode:
+		out = branchReferences(&typ.BranchNode, out)
+	case *parse.TemplateNode:
+		out = append(out, typ.Name)
+		out = references(typ.Pipe, out)
+	case *parse.PipeNode:
+		if typ == nil {
+			return out
+		}
+		for _, cmd := range typ.Cmds {
+			out = references(cmd, out)
+		}
+	case *parse.CommandNode:
+		for i, arg := range typ.Args {
+			ident, ok := arg.(*parse.IdentifierNode)
+			if ok && ident.Ident == "include" && i+1 < len(typ.Args) {
+				if name, ok := typ.Args[i+1].(*parse.StringNode); ok {
+					out = append(out, name.Text)
+				}
+			}
+			out = references(arg, out)
+		}
+	}
+	return out
+}
+
+func branchReferences(b *parse.BranchNode, out []string) []string {
+	out = references(b.Pipe, out)
+	out = references(b.List, out)
+	return references(b.ElseList, out)
 }
@@ -0,0 +1,268 @@
+package tmpl
+
+import (
+	"bytes"
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TemplateTestSuite struct {
+	suite.Suite
+}
+
+func (t *TemplateTestSuite) TestWithPartials() {
+	type test struct {
+		GivenPartials      []*Partial
+		Given              string
+		Data               map[string]any
+		Expected           string
+		ExpectedPartialErr string
+		ExpectedErr        string
+	}
+
+	tests := map[string]test{
+		"template by partial name": {
+			GivenPartials: []*Partial{{Name: "_license.tmpl", Content: []byte("// MIT {{ .year }}")}},
+			Given:         `{{ template "_license.tmpl" . }}` + "\npackage main",
+			Data:          map[string]any{"year": "2025"},
+			Expected:      "// MIT 2025\npackage main",
+		},
+		"template by define": {
+			GivenPartials: []*Partial{{Name: "_helpers.tmpl", Content: []byte(`{{ define "name" }}{{ .name | pascalCase }}{{ end }}`)}},
+			Given:         `type {{ template "name" . }} struct{}`,
+			Data:          map[string]any{"name": "http route"},
+			Expected:      "type HttpRoute struct{}",
+		},
+		"include can be piped": {
+			GivenPartials: []*Partial{{Name: "_labels.tmpl", Content: []byte("app: {{ .name }}\ntier: web")}},
+			Given:         `labels:{{ include "_labels.tmpl" . | nindent 2 }}`,
+			Data:          map[string]any{"name": "api"},
+			Expected:      "labels:\n  app: api\n  tier: web",
+		},
+		"partials reference each other": {
+			GivenPartials: []*Partial{
+				{Name: "_a", Content: []byte(`a{{ template "_b" . }}`)},
+				{Name: "_b", Content: []byte(`b`)},
+			},
+			Given:    `{{ include "_a" . }}`,
+			Expected: "ab",
+		},
+		"self cycle": {
+			GivenPartials:      []*Partial{{Name: "_a", Content: []byte(`{{ template "_a" . }}`)}},
+			ExpectedPartialErr: "partial cycle detected: _a -> _a",
+		},
+		"include cycle": {
+			GivenPartials: []*Partial{
+				{Name: "_a", Content: []byte(`{{ if .x }}{{ include "_b" . }}{{ end }}`)},
+				{Name: "_b", Content: []byte(`{{ range .y }}{{ include "_a" . | upper }}{{ end }}`)},
+			},
+			ExpectedPartialErr: "partial cycle detected: _a -> _b -> _a",
+		},
+		"dynamic include cycle": {
+			GivenPartials: []*Partial{{Name: "_a", Content: []byte(`{{ include .name . }}`)}},
+			Given:         `{{ include "_a" . }}`,
+			Data:          map[string]any{"name": "_a"},
+			ExpectedErr:   "include depth exceeded: _a -> _a",
+		},
+		"invalid partial": {
+			GivenPartials:      []*Partial{{Name: "_a", Content: []byte(`{{ .name `)}},
+			ExpectedPartialErr: "partial _a",
+		},
+		"missing partial": {
+			Given:       `{{ include "_missing" . }}`,
+			ExpectedErr: `no template "_missing"`,
+		},
+	}
+
+	for desc, v := range tests {
+		t.Run(desc, func() {
+			fact, err := NewGoFactory().WithPartials(v.GivenPartials...)
+			if v.ExpectedPartialErr != "" || !t.NoError(err) {
+				t.ErrorContains(err, v.ExpectedPartialErr)
+				return
+			}
+
+			tm, err := fact.NewTemplate([]byte(v.Given))
+			if !t.NoError(err) {
+				return
+			}
+
+			buf := bytes.NewBuffer(nil)
+			err = tm.Render(v.Data, buf)
+			if v.ExpectedErr != "" || !t.NoError(err) {
+				t.ErrorContains(err, v.ExpectedErr)
+				return
+			}
+
+			t.Equal(v.Expected, buf.String())
+		})
+	}
+}
+
+func (t *TemplateTestSuite) TestWithEngine() {
+	type test struct {
+		GivenEngine        Engine
+		GivenPartials      []*Partial
+		Given              string
+		Data               map[string]any
+		Expected           string
+		ExpectedEngineErr  string
+		ExpectedPartialErr string
+	}
+
+	tests := map[string]test{
+		"go": {
+			GivenEngine: EngineGo,
+			Given:       `name: {{ .name }}`,
+			Data:        map[string]any{"name": "api"},
+			Expected:    "name: api",
+		},
+		"angle leaves curly braces untouched": {
+			GivenEngine: EngineAngle,
+			Given:       `name: <% .name | kebabCase %>` + "\n" + `image: {{ .Values.image }}`,
+			Data:        map[string]any{"name": "myApi"},
+			Expected:    "name: my-api\nimage: {{ .Values.image }}",
+		},
+		"angle trims whitespace": {
+			GivenEngine: EngineAngle,
+			Given:       "a\n<%- if .ok %>\nb\n<%- end %>",
+			Data:        map[string]any{"ok": true},
+			Expected:    "a\nb",
+		},
+		"partials keep their own syntax": {
+			GivenEngine: EngineAngle,
+			GivenPartials: []*Partial{
+				{Name: "_go.tmpl", Content: []byte(`go {{ .name }}`)},
+				{Name: "_angle.atmpl", Content: []byte(`angle <% .name %> {{ .name }}`), Engine: EngineAngle},
+			},
+			Given:    `<% include "_go.tmpl" . %>, <% template "_angle.atmpl" . %>`,
+			Data:     map[string]any{"name": "api"},
+			Expected: "go api, angle api {{ .name }}",
+		},
+		"unknown engine": {
+			GivenEngine:       "jinja",
+			ExpectedEngineErr: "unknown template engine jinja",
+		},
+		"unknown partial engine": {
+			GivenEngine:        EngineGo,
+			GivenPartials:      []*Partial{{Name: "_a", Engine: "jinja"}},
+			ExpectedPartialErr: "partial _a: unknown template engine jinja",
+		},
+	}
+
+	for desc, v := range tests {
+		t.Run(desc, func() {
+			fact, err := NewGoFactory().WithPartials(v.GivenPartials...)
+			if v.ExpectedPartialErr != "" || !t.NoError(err) {
+				t.ErrorContains(err, v.ExpectedPartialErr)
+				return
+			}
+
+			fact, err = fact.WithEngine(v.GivenEngine)
+			if v.ExpectedEngineErr != "" || !t.NoError(err) {
+				t.ErrorContains(err, v.ExpectedEngineErr)
+				return
+			}
+
+			tm, err := fact.NewTemplate([]byte(v.Given))
+			if !t.NoError(err) {
+				return
+			}
+
+			buf := bytes.NewBuffer(nil)
+			if t.NoError(tm.Render(v.Data, buf)) {
+				t.Equal(v.Expected, buf.String())
+			}
+		})
+	}
+}
+
+func (t *TemplateTestSuite) TestEach() {
+	type test struct {
+		Given          string
+		Data           map[string]any
+		Expected       []any
+		ExpectedOK     bool
+		ExpectedRender string
+		ExpectedErr    string
+	}
+
+	tests := map[string]test{
+		"strings": {
+			Given:          `{{ each .names }}{{ .item }}.go`,
+			Data:           map[string]any{"names": []string{"a", "b"}},
+			Expected:       []any{"a", "b"},
+			ExpectedOK:     true,
+			ExpectedRender: "b.go",
+		},
+		"stops at each": {
+			Given:          `{{ if .ok }}{{ each .names }}{{ end }}{{ .item | upper }}.go`,
+			Data:           map[string]any{"ok": true, "names": []float64{1}, "item": "a"},
+			Expected:       []any{1.0},
+			ExpectedOK:     true,
+			ExpectedRender: "A.go",
+		},
+		"empty": {
+			Given:          `{{ each .names }}{{ .item }}.go`,
+			Data:           map[string]any{},
+			ExpectedOK:     true,
+			ExpectedRender: "b.go",
+		},
+		"not called": {
+			Given:          `{{ .item }}.go`,
+			Data:           map[string]any{"item": "b"},
+			ExpectedRender: "b.go",
+		},
+		"not a list": {
+			Given:       `{{ each .name }}`,
+			Data:        map[string]any{"name": "a"},
+			ExpectedErr: "expected a list but got string",
+		},
+	}
+
+	for desc, v := range tests {
+		t.Run(desc, func() {
+			tm, err := NewGoFactory().NewTemplate([]byte(v.Given))
+			if !t.NoError(err) {
+				return
+			}
+
+			actual, ok, err := tm.Each(v.Data)
+			if v.ExpectedErr != "" || !t.NoError(err) {
+				t.ErrorContains(err, v.ExpectedErr)
+				return
+			}
+			t.Equal(v.Expected, actual)
+			t.Equal(v.ExpectedOK, ok)
+
+			buf := bytes.NewBuffer(nil)
+			data := map[string]any{"item": "b"}
+			maps.Copy(data, v.Data)
+			if t.NoError(tm.Render(data, buf)) {
+				t.Equal(v.ExpectedRender, buf.String())
+			}
+		})
+	}
+}
+
+func (t *TemplateTestSuite) TestEngineForPath() {
+	tests := map[string]Engine{
+		"templates/controller.tmpl":             EngineGo,
+		"templates/main.go.gotmpl":              EngineGo,
+		"chart/templates/deployment.yaml.atmpl": EngineAngle,
+		"README.md":                             EngineGo,
+		"Makefile":                              EngineGo,
+	}
+
+	for given, expected := range tests {
+		t.Run(given, func() {
+			t.Equal(expected, EngineForPath(given))
+		})
+	}
+}
+
+func TestTemplateTestSuite(t *testing.T) {
+	suite.Run(t, new(TemplateTestSuite))
+}